@@ -8,6 +8,7 @@ import (
 	stdContext "context"
 
 	"../localtunnelme"
+	"../spark"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/betacraft/yaag/irisyaag"
@@ -30,6 +31,17 @@ func init() {
 	a.Log = logrus.New()
 	a.Server = iris.New()
 	a.Tunnel = localtunnelme.NewTunnel()
+	a.Spark = ciscospark.NewClient(nil)
+	a.Commands = NewCommandRouter()
+	a.Intents = NewIntentRouter()
+	a.Cards = NewCardRouter()
+	a.Events = NewEventRouter()
+	a.bot = new(ciscospark.Person)
+	a.botMu = new(sync.Mutex)
+	a.seen = newSeenSet()
+	a.queue = newEventQueue()
+	a.queue.register()
+	a.Store = NewMemoryStore()
 	numCPU := runtime.NumCPU()
 	a.Log.Info("Initialising application...")
 	a.setDefaultsConfig()
@@ -61,6 +73,31 @@ func (a Application) setDefaultsConfig() {
 	a.conf.Set("server.config.enablepathescape", true)
 	a.conf.Set("server.config.firemethodnotallowed", false)
 	a.conf.Set("server.config.timeformat", "Mon, 02 Jan 2006 15:04:05 GMT")
+	a.conf.Set("spark.timeout", 30)
+	a.conf.Set("spark.retries", 3)
+	a.conf.Set("spark.insecureskipverify", false)
+	a.conf.Set("spark.cafile", "")
+	a.conf.Set("spark.proxy", "")
+	a.conf.Set("spark.baseurl", "")
+	a.conf.Set("spark.maxcontentsize", ciscospark.DefaultMaxContentSize)
+	a.conf.Set("spark.identity.attempts", 5)
+	a.conf.Set("spark.webhooks", "messages:created,attachmentActions:created,memberships:created")
+	a.conf.Set("spark.webhook.removeonshutdown", false)
+	a.conf.Set("spark.dedupe.ttl", 600)
+	a.conf.Set("spark.dedupe.file", "")
+	a.conf.Set("spark.queue.workers", 4)
+	a.conf.Set("spark.queue.depth", 100)
+	a.conf.Set("spark.queue.timeout", 60)
+	a.conf.Set("spark.queue.deadletter", "")
+	a.conf.Set("spark.store.file", "")
+	a.conf.Set("spark.tokenenv", "SPARK_TOKEN")
+	a.conf.Set("spark.oauth.clientid", "")
+	a.conf.Set("spark.oauth.clientsecret", "")
+	a.conf.Set("spark.oauth.redirecturl", "")
+	a.conf.Set("spark.oauth.scopes", "spark:all")
+	a.conf.Set("spark.oauth.tokenfile", "")
+	a.conf.Set("spark.oauth.loginsecret", "")
+	a.conf.Set("spark.oauth.reauthorize", false)
 }
 
 func (a Application) setServerConfig() {
@@ -76,6 +113,9 @@ func (a Application) setServerConfig() {
 		defer cancel()
 		// close all hosts
 		a.Log.Info("Shutting down server....")
+		if err := a.queue.drain(timeout); err != nil {
+			a.Log.Error(err)
+		}
 		a.Stop()
 		a.Log.Info("Wait ", a.conf.GetInt("server.timeout"), " seconds and check your terminal again")
 		time.Sleep(time.Duration(a.conf.GetInt("server.timeout")) * time.Second)
@@ -93,6 +133,9 @@ func (a Application) setServerConfig() {
 		a.Server.Use(irisyaag.New()) // <- IMPORTANT, register the middleware.
 	}
 	a.addRoutes()
+	a.addEvents()
+	a.addCommands()
+	a.addIntents()
 }
 
 // GET
@@ -126,6 +169,28 @@ func (a Application) LoadConfig(data string) {
 		a.Log.Level = logrus.InfoLevel
 		a.Log.Info("Info Logging has been initialised...")
 	}
+	if err := a.configureSparkClient(); err != nil {
+		panic(err)
+	}
+	if path := a.conf.GetString("spark.store.file"); path != "" {
+		store, err := NewFileStore(path)
+		if err != nil {
+			panic(err)
+		}
+		New().Store = store
+	}
+	ttl := time.Duration(a.conf.GetInt("spark.dedupe.ttl")) * time.Second
+	if err := a.seen.configure(ttl, a.conf.GetString("spark.dedupe.file")); err != nil {
+		a.Log.Error("DEDUPE: ", err)
+	}
+	if a.conf.GetString("spark.secret") == "" {
+		secret, err := randomHex(32)
+		if err != nil {
+			panic(err)
+		}
+		a.conf.Set("spark.secret", secret)
+		a.Log.Warn("spark.secret is not set, webhooks are registered with a generated secret")
+	}
 }
 func (a Application) createLocalTunnelMe() bool {
 	a.Log.Info("Initialising LocalTunnel.Me config....")
@@ -146,15 +211,51 @@ func (a Application) createLocalTunnelMe() bool {
 }
 
 func (a Application) Stop() {
+	if a.conf.GetBool("spark.webhook.removeonshutdown") {
+		timeout := time.Duration(a.conf.GetInt("server.timeout")) * time.Second
+		ctx, cancel := stdContext.WithTimeout(stdContext.Background(), timeout)
+		if err := a.removeWebhooks(ctx); err != nil {
+			a.Log.Error(err)
+		}
+		cancel()
+	}
 	a.Tunnel.StopTunnel()
 	a.Log.Info("LocalTunnelMe service has been stopped successfully....")
 }
 
 func (a Application) Run() {
 	var serverConfig iris.Configuration
-	a.createLocalTunnelMe()
-	//deleteWebHooks()
-	//registerWebHook()
+	if a.oauth != nil && !a.oauth.hasToken() {
+		a.Log.Warn("IDENTITY: events are dropped until the integration is authorized")
+	} else if err := a.resolveBot(stdContext.Background(), a.Spark); err != nil {
+		panic(err)
+	}
+	if err := a.startQueue(); err != nil {
+		panic(err)
+	}
+	if a.createLocalTunnelMe() {
+		if a.oauth != nil && a.oauth.config.RedirectURL == "" {
+			a.oauth.config.RedirectURL = a.conf.GetString("server.localtunnel.url") + "/oauth/callback"
+		}
+		if a.oauth != nil && a.oauth.needsLogin() {
+			login := a.conf.GetString("server.localtunnel.url") + "/oauth/login?secret="
+			if a.oauth.generated {
+				login += a.oauth.loginSecret
+			} else {
+				login += "<spark.oauth.loginsecret>"
+			}
+			a.Log.Info("OAUTH: authorize the integration at ", login)
+		}
+		target := a.conf.GetString("server.localtunnel.url") + "/callback"
+		timeout := time.Duration(a.conf.GetInt("server.timeout")) * time.Second
+		ctx, cancel := stdContext.WithTimeout(stdContext.Background(), timeout)
+		if err := a.reconcileWebhooks(ctx, target); err != nil {
+			a.Log.Error("WEBHOOK: ", err)
+		}
+		cancel()
+	} else {
+		a.Log.Warn("WEBHOOK: webhooks were not registered, callbacks not signed with spark.secret are rejected")
+	}
 	serverConfig.Charset = a.conf.GetString("server.config.charset")
 	serverConfig.DisableAutoFireStatusCode = a.conf.GetBool("server.config.disableautofirestatuscode")
 	serverConfig.DisableBodyConsumptionOnUnmarshal = a.conf.GetBool("server.config.disablebodyconsumptiononunmarshal")
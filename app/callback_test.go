@@ -0,0 +1,67 @@
+package app
+
+import (
+	stdContext "context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"../spark"
+	"../spark/sparktest"
+)
+
+func TestCallback_RepliesToCommand(t *testing.T) {
+	s := sparktest.NewServer(nil)
+	defer s.Close()
+	alice := s.AddPerson(&ciscospark.Person{DisplayName: "Alice", Emails: []string{"alice@example.com"}})
+	room := s.AddRoom(&ciscospark.Room{Title: "team"})
+
+	a := New()
+	a.seen = newSeenSet()
+	a.Spark = s.Client()
+	if err := a.resolveBot(stdContext.Background(), a.Spark); err != nil {
+		t.Fatal(err)
+	}
+	a.conf.Set("spark.secret", "s3cret")
+	a.conf.Set("spark.hookname", "sparkbot-test")
+	if err := a.Server.Build(); err != nil {
+		t.Fatal(err)
+	}
+	bot := httptest.NewServer(a.Server)
+	defer bot.Close()
+	if err := a.startQueue(); err != nil {
+		t.Fatal(err)
+	}
+	defer a.queue.drain(time.Second)
+	if err := a.reconcileWebhooks(stdContext.Background(), bot.URL+"/callback"); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := http.Post(bot.URL+"/callback", "application/json", strings.NewReader(`{"resource":"messages","event":"created"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("unsigned callback: status = %d, want 401", resp.StatusCode)
+	}
+
+	message := s.AddMessage(&ciscospark.Message{RoomID: room.ID, PersonID: alice.ID, Text: "Sparkbot: /hello"})
+	if err := s.FireWebhooks(ciscospark.ResourceMessages, ciscospark.EventCreated, message); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "Hello <@personEmail:alice@example.com>"
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, m := range s.Messages(room.ID) {
+			if m.PersonID == s.Me().ID && m.MarkDown == want {
+				return
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Errorf("no reply %q in %v", want, s.Messages(room.ID))
+}
@@ -0,0 +1,91 @@
+package app
+
+import (
+	stdContext "context"
+	"fmt"
+	"sync"
+
+	"../spark"
+)
+
+// cardIDInput is the Action.Submit data field naming the card a submission belongs to
+const cardIDInput = "cardId"
+
+// CardContext carries a card submission. Replies go to the room of the card.
+type CardContext struct {
+	*CommandContext
+
+	// CardID is the cardId sent with the submit action
+	CardID string
+	Action *ciscospark.AttachmentAction
+}
+
+// CardHandler handles the submissions of a card
+type CardHandler func(*CardContext) error
+
+// CardRouter dispatches card submissions to the handler registered for their card ID
+type CardRouter struct {
+	mu       sync.RWMutex
+	handlers map[string]CardHandler
+}
+
+// NewCardContext returns the card context for a submission received in ctx
+func NewCardContext(ctx stdContext.Context, sparkClient *ciscospark.Client, action *ciscospark.AttachmentAction) *CardContext {
+	c := &CardContext{
+		CommandContext: NewCommandContext(ctx, sparkClient, &ciscospark.Message{
+			ID:       action.MessageID,
+			RoomID:   action.RoomID,
+			PersonID: action.PersonID,
+		}),
+		Action: action,
+	}
+	c.CardID = c.Input(cardIDInput)
+	return c
+}
+
+// Input returns the submitted value of the named input, or of a field of the
+// submit action data
+func (c *CardContext) Input(name string) string {
+	v, ok := c.Action.Inputs[name]
+	if !ok || v == nil {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
+
+// NewCardSubmitAction returns a submit button routed to the handler of cardID,
+// data is sent along with the card inputs
+func NewCardSubmitAction(cardID, title string, data map[string]interface{}) ciscospark.CardAction {
+	fields := map[string]interface{}{cardIDInput: cardID}
+	for k, v := range data {
+		fields[k] = v
+	}
+	return ciscospark.NewSubmitAction(title, fields)
+}
+
+// NewCardRouter returns a router with no cards registered
+func NewCardRouter() *CardRouter {
+	return &CardRouter{handlers: make(map[string]CardHandler)}
+}
+
+// Handle registers h for submissions of the card with the given ID
+func (r *CardRouter) Handle(cardID string, h CardHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers[cardID] = h
+}
+
+// Dispatch runs the handler registered for the card of the submission
+func (r *CardRouter) Dispatch(c *CardContext) error {
+	r.mu.RLock()
+	h, ok := r.handlers[c.CardID]
+	r.mu.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("no handler for card %q", c.CardID)
+	}
+	return h(c)
+}
@@ -0,0 +1,80 @@
+package app
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"../spark"
+)
+
+// configureSparkClient applies the spark.* settings to the shared Spark client.
+// TLS certificates are verified unless spark.insecureskipverify is set.
+func (a Application) configureSparkClient() error {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: a.conf.GetBool("spark.insecureskipverify"),
+	}
+	if a.conf.GetBool("spark.insecureskipverify") {
+		a.Log.Warn("spark.insecureskipverify is set, Spark API certificates will not be verified")
+	}
+	if caFile := a.conf.GetString("spark.cafile"); caFile != "" {
+		pem, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return err
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return errors.New("spark.cafile: no certificates found in " + caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	tr := &http.Transport{
+		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: tlsConfig,
+	}
+	if proxy := a.conf.GetString("spark.proxy"); proxy != "" {
+		proxyURL, err := url.Parse(proxy)
+		if err != nil {
+			return err
+		}
+		tr.Proxy = http.ProxyURL(proxyURL)
+	}
+	client := &http.Client{
+		Transport: tr,
+		Timeout:   time.Duration(a.conf.GetInt("spark.timeout")) * time.Second,
+	}
+
+	tokenSource, err := a.sparkTokenSource(client)
+	if err != nil {
+		return err
+	}
+	opts := []ciscospark.ClientOpt{
+		ciscospark.SetHTTPClient(client),
+		ciscospark.SetTokenSource(tokenSource),
+		ciscospark.SetRetryPolicy(ciscospark.RetryPolicy{
+			MaxRetries: a.conf.GetInt("spark.retries"),
+			MinBackoff: ciscospark.DefaultRetryPolicy.MinBackoff,
+			MaxBackoff: ciscospark.DefaultRetryPolicy.MaxBackoff,
+		}),
+	}
+	if size := a.conf.GetInt("spark.maxcontentsize"); size > 0 {
+		opts = append(opts, ciscospark.SetMaxContentSize(int64(size)))
+	}
+	if baseURL := a.conf.GetString("spark.baseurl"); baseURL != "" {
+		opts = append(opts, ciscospark.SetBaseURL(baseURL))
+	}
+	for _, opt := range opts {
+		if err := opt(a.Spark); err != nil {
+			return err
+		}
+	}
+	return nil
+}
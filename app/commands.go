@@ -0,0 +1,222 @@
+package app
+
+import (
+	stdContext "context"
+	"strings"
+	"sync"
+	"unicode"
+
+	"../spark"
+)
+
+// CommandContext carries the message a command was triggered by
+type CommandContext struct {
+	ctx stdContext.Context
+
+	// Spark is the client replies are posted with
+	Spark *ciscospark.Client
+
+	RoomID      string
+	RoomType    string
+	PersonID    string
+	PersonEmail string
+	// Text is the message text with the bot mention removed
+	Text string
+	// Args is the part of Text following the command name
+	Args    string
+	Message *ciscospark.Message
+
+	store Store
+}
+
+// StatusMessage is a message posted by the bot that can be edited in place
+type StatusMessage struct {
+	spark *ciscospark.Client
+	*ciscospark.Message
+}
+
+// Update replaces the markdown of the status message
+func (m *StatusMessage) Update(ctx stdContext.Context, markdown string) error {
+	message, _, err := m.spark.Messages.UpdateMessage(ctx, m.ID, &ciscospark.UpdateMessageRequest{
+		RoomID:   m.RoomID,
+		MarkDown: markdown,
+	})
+	if err != nil {
+		return err
+	}
+	m.Message = message
+	return nil
+}
+
+// CommandHandler handles a command sent to the bot
+type CommandHandler func(*CommandContext) error
+
+// CommandRouter dispatches messages to the handler registered for their first word
+type CommandRouter struct {
+	mu       sync.RWMutex
+	commands map[string]CommandHandler
+	fallback CommandHandler
+	mentions []string
+}
+
+// NewCommandContext returns the command context for a message received in ctx
+func NewCommandContext(ctx stdContext.Context, sparkClient *ciscospark.Client, message *ciscospark.Message) *CommandContext {
+	return &CommandContext{
+		ctx:         ctx,
+		Spark:       sparkClient,
+		RoomID:      message.RoomID,
+		RoomType:    message.RoomType,
+		PersonID:    message.PersonID,
+		PersonEmail: message.PersonEmail,
+		Text:        strings.TrimSpace(message.Text),
+		Message:     message,
+		store:       New().Store,
+	}
+}
+
+// Context returns the context of the request the command arrived with
+func (c *CommandContext) Context() stdContext.Context { return c.ctx }
+
+// Reply posts a markdown message to the room the command came from
+func (c *CommandContext) Reply(markdown string) error {
+	_, err := postSparkMessage(c.ctx, c.Spark, &ciscospark.MessageRequest{
+		RoomID:   c.RoomID,
+		MarkDown: markdown,
+	})
+	return err
+}
+
+// ReplyInThread posts a markdown message as a threaded reply to the command
+func (c *CommandContext) ReplyInThread(markdown string) error {
+	_, err := postSparkMessage(c.ctx, c.Spark, &ciscospark.MessageRequest{
+		RoomID:   c.RoomID,
+		MarkDown: markdown,
+		ParentID: c.threadID(),
+	})
+	return err
+}
+
+// PostStatus posts a markdown message in the thread of the command that can
+// later be edited, so long running jobs report progress in a single message
+func (c *CommandContext) PostStatus(markdown string) (*StatusMessage, error) {
+	message, err := postSparkMessage(c.ctx, c.Spark, &ciscospark.MessageRequest{
+		RoomID:   c.RoomID,
+		MarkDown: markdown,
+		ParentID: c.threadID(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &StatusMessage{spark: c.Spark, Message: message}, nil
+}
+
+// threadID returns the ID of the thread the command belongs to, replies to a
+// reply go to the same thread
+func (c *CommandContext) threadID() string {
+	if c.Message.ParentID != "" {
+		return c.Message.ParentID
+	}
+	return c.Message.ID
+}
+
+// ReplyWithFile posts a markdown message with an uploaded file to the room the command came from
+func (c *CommandContext) ReplyWithFile(markdown string, file *ciscospark.FileUpload) error {
+	_, err := postSparkMessage(c.ctx, c.Spark, &ciscospark.MessageRequest{
+		RoomID:   c.RoomID,
+		MarkDown: markdown,
+		Upload:   file,
+	})
+	return err
+}
+
+// ReplyWithCard posts an Adaptive Card to the room the command came from,
+// markdown is shown by clients that can't render cards
+func (c *CommandContext) ReplyWithCard(markdown string, card *ciscospark.AdaptiveCard) error {
+	_, err := postSparkMessage(c.ctx, c.Spark, &ciscospark.MessageRequest{
+		RoomID:      c.RoomID,
+		MarkDown:    markdown,
+		Attachments: []ciscospark.Attachment{ciscospark.NewCardAttachment(card)},
+	})
+	return err
+}
+
+// ReplyToPerson posts a markdown message directly to the person who sent the command
+func (c *CommandContext) ReplyToPerson(markdown string) error {
+	_, err := postSparkMessage(c.ctx, c.Spark, &ciscospark.MessageRequest{
+		ToPersonEmail: c.PersonEmail,
+		MarkDown:      markdown,
+	})
+	return err
+}
+
+// NewCommandRouter returns a router with no commands registered
+func NewCommandRouter() *CommandRouter {
+	return &CommandRouter{commands: make(map[string]CommandHandler)}
+}
+
+// Handle registers h for messages whose first word is name, ignoring case
+func (r *CommandRouter) Handle(name string, h CommandHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.commands[strings.ToLower(name)] = h
+}
+
+// HandleFallback registers h for messages that match no command
+func (r *CommandRouter) HandleFallback(h CommandHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fallback = h
+}
+
+// SetMentionNames sets the names the bot is mentioned by in group rooms
+func (r *CommandRouter) SetMentionNames(names ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mentions = names
+}
+
+// Dispatch strips the bot mention from group room messages and runs the
+// matching command, or the fallback if there is none
+func (r *CommandRouter) Dispatch(c *CommandContext) error {
+	r.mu.RLock()
+	if c.RoomType == "group" {
+		c.Text = stripMention(c.Text, r.mentions)
+	}
+	name, args := splitCommand(c.Text)
+	h, ok := r.commands[strings.ToLower(name)]
+	if !ok {
+		h = r.fallback
+	}
+	r.mu.RUnlock()
+
+	c.Args = args
+	if h == nil {
+		return nil
+	}
+	return h(c)
+}
+
+// stripMention removes the first of names that prefixes text, along with a
+// colon following it as in "Sparkbot: /help"
+func stripMention(text string, names []string) string {
+	for _, name := range names {
+		if name == "" || len(text) < len(name) {
+			continue
+		}
+		rest := strings.TrimPrefix(text[len(name):], ":")
+		if strings.EqualFold(text[:len(name)], name) && (rest == "" || unicode.IsSpace(rune(rest[0]))) {
+			return strings.TrimSpace(rest)
+		}
+	}
+	return text
+}
+
+// splitCommand splits text into its first word and the rest, at any run of whitespace
+func splitCommand(text string) (string, string) {
+	text = strings.TrimSpace(text)
+	i := strings.IndexFunc(text, unicode.IsSpace)
+	if i < 0 {
+		return text, ""
+	}
+	return text[:i], strings.TrimSpace(text[i:])
+}
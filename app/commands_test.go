@@ -0,0 +1,84 @@
+package app
+
+import "testing"
+
+func TestStripMention(t *testing.T) {
+	names := []string{"Sparkbot", "Spark"}
+	tests := []struct {
+		text, want string
+	}{
+		{"Sparkbot /help", "/help"},
+		{"Sparkbot: /help", "/help"},
+		{"sparkbot:   /hello  there ", "/hello  there"},
+		{"Sparkbot", ""},
+		{"Sparkbot:", ""},
+		{"Spark /about", "/about"},
+		{"Sparkbots /help", "Sparkbots /help"},
+		{"/help Sparkbot", "/help Sparkbot"},
+		{"Sparkbot\t/help", "/help"},
+	}
+	for _, tt := range tests {
+		if got := stripMention(tt.text, names); got != tt.want {
+			t.Errorf("stripMention(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		text, name, args string
+	}{
+		{"/help", "/help", ""},
+		{"/echo hello world", "/echo", "hello world"},
+		{"  /echo    hello  ", "/echo", "hello"},
+		{"/echo\thello\nworld", "/echo", "hello\nworld"},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		name, args := splitCommand(tt.text)
+		if name != tt.name || args != tt.args {
+			t.Errorf("splitCommand(%q) = %q, %q, want %q, %q", tt.text, name, args, tt.name, tt.args)
+		}
+	}
+}
+
+func TestCommandRouter_Dispatch(t *testing.T) {
+	r := NewCommandRouter()
+	r.SetMentionNames("Sparkbot")
+	var handled, args string
+	r.Handle("/Echo", func(c *CommandContext) error {
+		handled, args = "echo", c.Args
+		return nil
+	})
+	r.HandleFallback(func(c *CommandContext) error {
+		handled, args = "fallback", c.Text
+		return nil
+	})
+
+	tests := []struct {
+		roomType, text, handled, args string
+	}{
+		{"direct", "/echo hi", "echo", "hi"},
+		{"direct", "/ECHO  hi  there", "echo", "hi  there"},
+		{"group", "Sparkbot: /echo hi", "echo", "hi"},
+		{"group", "Sparkbot /echo", "echo", ""},
+		{"direct", "Sparkbot /echo hi", "fallback", "Sparkbot /echo hi"},
+		{"direct", "/unknown hi", "fallback", "/unknown hi"},
+		{"group", "Sparkbot what's up", "fallback", "what's up"},
+	}
+	for _, tt := range tests {
+		handled, args = "", ""
+		c := &CommandContext{RoomType: tt.roomType, Text: tt.text}
+		if err := r.Dispatch(c); err != nil {
+			t.Fatal(err)
+		}
+		if handled != tt.handled || args != tt.args {
+			t.Errorf("%s %q: handled by %q with %q, want %q with %q", tt.roomType, tt.text, handled, args, tt.handled, tt.args)
+		}
+	}
+
+	r.HandleFallback(nil)
+	if err := r.Dispatch(&CommandContext{Text: "/unknown"}); err != nil {
+		t.Errorf("unknown command without fallback: %v", err)
+	}
+}
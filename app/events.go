@@ -0,0 +1,73 @@
+package app
+
+import (
+	stdContext "context"
+	"sync"
+
+	"../spark"
+)
+
+// EventContext carries a webhook event. Replies go to the room the event happened in.
+type EventContext struct {
+	*CommandContext
+
+	Event *ciscospark.WebhookEvent
+}
+
+// EventHandler handles a webhook event
+type EventHandler func(*EventContext) error
+
+// EventRouter dispatches webhook events to the handler registered for their resource and event
+type EventRouter struct {
+	mu       sync.RWMutex
+	handlers map[string]EventHandler
+}
+
+// NewEventContext returns the event context for a webhook event received in ctx
+func NewEventContext(ctx stdContext.Context, sparkClient *ciscospark.Client, event *ciscospark.WebhookEvent) *EventContext {
+	message := new(ciscospark.Message)
+	switch data := event.Data.(type) {
+	case *ciscospark.Message:
+		message = data
+	case *ciscospark.Membership:
+		message.RoomID, message.RoomType = data.RoomID, data.RoomType
+		message.PersonID, message.PersonEmail = data.PersonID, data.PersonEmail
+	case *ciscospark.Room:
+		message.RoomID, message.RoomType = data.ID, data.Type
+	case *ciscospark.AttachmentAction:
+		message.ID, message.RoomID, message.PersonID = data.MessageID, data.RoomID, data.PersonID
+	}
+	return &EventContext{
+		CommandContext: NewCommandContext(ctx, sparkClient, message),
+		Event:          event,
+	}
+}
+
+// NewEventRouter returns a router with no events registered
+func NewEventRouter() *EventRouter {
+	return &EventRouter{handlers: make(map[string]EventHandler)}
+}
+
+// Handle registers h for events of resource, such as "memberships", and event,
+// such as "created". An event of "all" matches every event of the resource.
+func (r *EventRouter) Handle(resource, event string, h EventHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers[resource+":"+event] = h
+}
+
+// Dispatch runs the handler registered for the event, events nobody
+// subscribed to are ignored
+func (r *EventRouter) Dispatch(c *EventContext) error {
+	r.mu.RLock()
+	h, ok := r.handlers[c.Event.Resource+":"+c.Event.Event]
+	if !ok {
+		h, ok = r.handlers[c.Event.Resource+":"+ciscospark.EventAll]
+	}
+	r.mu.RUnlock()
+
+	if !ok {
+		return nil
+	}
+	return h(c)
+}
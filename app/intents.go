@@ -0,0 +1,259 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"../nlp"
+)
+
+// pendingIntentTTL is how long the bot waits for the answer to a prompt
+const pendingIntentTTL = 10 * time.Minute
+
+// slot is an intent field marked with an nlp:"required" tag
+type slot struct {
+	name   string
+	prompt string
+}
+
+// pendingIntent is an intent waiting for the answers to its prompts
+type pendingIntent struct {
+	Intent string          `json:"intent"`
+	Value  json.RawMessage `json:"value"`
+	Filled []string        `json:"filled"`
+}
+
+var (
+	commandContextType = reflect.TypeOf((*CommandContext)(nil))
+	errorType          = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// IntentRouter runs messages through a trained nlp.NL and dispatches the
+// filled intent struct to the handler registered for its type
+type IntentRouter struct {
+	nl       *nlp.NL
+	handlers map[reflect.Type]reflect.Value
+	triggers map[reflect.Type][]string
+	slots    map[reflect.Type][]slot
+	types    map[string]reflect.Type
+	fallback CommandHandler
+	trained  bool
+}
+
+// NewIntentRouter returns a router with no intents registered
+func NewIntentRouter() *IntentRouter {
+	return &IntentRouter{
+		nl:       nlp.New(),
+		handlers: make(map[reflect.Type]reflect.Value),
+		triggers: make(map[reflect.Type][]string),
+		slots:    make(map[reflect.Type][]slot),
+		types:    make(map[string]reflect.Type),
+	}
+}
+
+// Register adds an intent model, the samples it is trained with and its handler.
+// The handler must be a func(*CommandContext, *T) error where T is the type of model.
+// Samples follow the nlp format, e.g. "remind me to {Task} in {In}".
+// Fields tagged nlp:"required,prompt=Which service?" are asked for when a
+// message leaves them out, the answers are merged before the handler runs.
+// Options such as nlp.WithTimeFormat apply to the samples and the answers.
+func (r *IntentRouter) Register(model interface{}, samples []string, handler interface{}, opts ...nlp.ModelOption) error {
+	t := reflect.TypeOf(model)
+	if t == nil || t.Kind() != reflect.Struct {
+		return fmt.Errorf("intent model must be a struct, got %T", model)
+	}
+	h := reflect.ValueOf(handler)
+	ht := h.Type()
+	if ht.Kind() != reflect.Func || ht.NumIn() != 2 || ht.NumOut() != 1 ||
+		ht.In(0) != commandContextType || ht.In(1) != reflect.PtrTo(t) || ht.Out(0) != errorType {
+		return fmt.Errorf("intent handler for %s must be a func(*CommandContext, *%s) error", t, t)
+	}
+	if err := r.nl.RegisterModel(model, samples, opts...); err != nil {
+		return fmt.Errorf("intent %s: %v", t, err)
+	}
+	r.handlers[t] = h
+	r.types[t.String()] = t
+	r.slots[t] = requiredSlots(t)
+	for _, sample := range samples {
+		r.triggers[t] = append(r.triggers[t], leadingPhrase(sample))
+	}
+	return nil
+}
+
+// Learn trains the registered intents, it must be called before Dispatch
+func (r *IntentRouter) Learn() error {
+	if err := r.nl.Learn(); err != nil {
+		return err
+	}
+	r.trained = true
+	return nil
+}
+
+// HandleFallback registers h for messages that match no intent
+func (r *IntentRouter) HandleFallback(h CommandHandler) {
+	r.fallback = h
+}
+
+// Dispatch parses the command text into the closest intent and runs its
+// handler. Messages that do not contain the leading words of any of the
+// intent samples, or that fill none of its fields, go to the fallback.
+// A message answering the prompt for a required field is merged into the
+// intent waiting for it instead.
+func (r *IntentRouter) Dispatch(c *CommandContext) error {
+	if r.trained && c.Text != "" {
+		if pending, ok := r.pending(c); ok {
+			return r.answer(c, pending)
+		}
+		res := r.nl.Process(c.Text)
+		v := reflect.ValueOf(res.Value)
+		if v.Kind() == reflect.Ptr && r.triggered(v.Elem().Type(), c.Text) &&
+			(len(res.Filled) > 0 || len(r.slots[v.Elem().Type()]) > 0) {
+			if _, ok := r.handlers[v.Elem().Type()]; ok {
+				return r.fill(c, v, res.Filled)
+			}
+		}
+	}
+	if r.fallback == nil {
+		return nil
+	}
+	return r.fallback(c)
+}
+
+// fill prompts for the first missing required field of the intent v, or
+// runs its handler once they are all filled
+func (r *IntentRouter) fill(c *CommandContext, v reflect.Value, filled []string) error {
+	t := v.Elem().Type()
+	for _, s := range r.slots[t] {
+		if contains(filled, s.name) {
+			continue
+		}
+		value, err := json.Marshal(v.Interface())
+		if err != nil {
+			return err
+		}
+		err = c.PersonState().Set(r.pendingKey(c), pendingIntent{Intent: t.String(), Value: value, Filled: filled}, pendingIntentTTL)
+		if err != nil {
+			return err
+		}
+		return c.Reply(s.prompt)
+	}
+	if err := c.PersonState().Delete(r.pendingKey(c)); err != nil {
+		return err
+	}
+	out := r.handlers[t].Call([]reflect.Value{reflect.ValueOf(c), v})
+	if err, _ := out[0].Interface().(error); err != nil {
+		return err
+	}
+	return nil
+}
+
+// pending returns the intent waiting for an answer from the sender in this room
+func (r *IntentRouter) pending(c *CommandContext) (*pendingIntent, bool) {
+	if c.store == nil {
+		return nil, false
+	}
+	pending := new(pendingIntent)
+	ok, err := c.PersonState().Get(r.pendingKey(c), pending)
+	if err != nil || !ok {
+		return nil, false
+	}
+	if _, ok := r.types[pending.Intent]; !ok {
+		return nil, false
+	}
+	return pending, true
+}
+
+// answer sets the field prompted for to the command text, "cancel" drops the intent
+func (r *IntentRouter) answer(c *CommandContext, pending *pendingIntent) error {
+	if strings.EqualFold(c.Text, "cancel") {
+		if err := c.PersonState().Delete(r.pendingKey(c)); err != nil {
+			return err
+		}
+		return c.Reply("OK, cancelled.")
+	}
+	t := r.types[pending.Intent]
+	v := reflect.New(t)
+	if err := json.Unmarshal(pending.Value, v.Interface()); err != nil {
+		return err
+	}
+	for _, s := range r.slots[t] {
+		if contains(pending.Filled, s.name) {
+			continue
+		}
+		if err := r.nl.SetField(v.Interface(), s.name, strings.TrimSpace(c.Text)); err != nil {
+			return c.Reply("Sorry, that is not a valid answer. " + s.prompt)
+		}
+		return r.fill(c, v, append(pending.Filled, s.name))
+	}
+	return r.fill(c, v, pending.Filled)
+}
+
+func (r *IntentRouter) pendingKey(c *CommandContext) string {
+	return "intent/" + c.RoomID
+}
+
+// requiredSlots returns the fields of t tagged nlp:"required", a prompt
+// option holds the rest of the tag, commas included
+func requiredSlots(t reflect.Type) []slot {
+	var slots []slot
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, ok := f.Tag.Lookup("nlp")
+		if !ok {
+			continue
+		}
+		s := slot{name: f.Name, prompt: "What is the " + strings.ToLower(f.Name) + "?"}
+		required := false
+		for tag != "" {
+			var opt string
+			if strings.HasPrefix(tag, "prompt=") {
+				opt, tag = tag, ""
+			} else if i := strings.Index(tag, ","); i >= 0 {
+				opt, tag = tag[:i], tag[i+1:]
+			} else {
+				opt, tag = tag, ""
+			}
+			switch {
+			case opt == "required":
+				required = true
+			case strings.HasPrefix(opt, "prompt="):
+				s.prompt = strings.TrimPrefix(opt, "prompt=")
+			}
+		}
+		if required {
+			slots = append(slots, s)
+		}
+	}
+	return slots
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// triggered reports whether text contains the leading phrase of one of the samples of t
+func (r *IntentRouter) triggered(t reflect.Type, text string) bool {
+	text = " " + strings.Join(strings.Fields(strings.ToLower(text)), " ") + " "
+	for _, phrase := range r.triggers[t] {
+		if strings.Contains(text, " "+phrase+" ") || phrase == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// leadingPhrase returns the words of sample before its first {Field}
+func leadingPhrase(sample string) string {
+	if i := strings.Index(sample, "{"); i >= 0 {
+		sample = sample[:i]
+	}
+	return strings.Join(strings.Fields(strings.ToLower(sample)), " ")
+}
@@ -0,0 +1,116 @@
+package app
+
+import (
+	stdContext "context"
+	"testing"
+	"time"
+
+	"../nlp"
+	"../spark"
+	"../spark/sparktest"
+)
+
+type deployIntent struct {
+	Service string    `nlp:"required,prompt=Which service?"`
+	At      time.Time `nlp:"required,prompt=When? e.g. 14:30"`
+}
+
+func TestIntentRouter_Prompts(t *testing.T) {
+	s := sparktest.NewServer(nil)
+	defer s.Close()
+	alice := s.AddPerson(&ciscospark.Person{DisplayName: "Alice", Emails: []string{"alice@example.com"}})
+	room := s.AddRoom(&ciscospark.Room{Title: "deploys", Type: "direct"})
+	client := s.Client()
+	store := NewMemoryStore()
+
+	var deployed []deployIntent
+	fallbacks := 0
+	r := NewIntentRouter()
+	err := r.Register(deployIntent{}, []string{
+		"deploy {Service} at {At}",
+		"deploy {Service}",
+	}, func(c *CommandContext, d *deployIntent) error {
+		deployed = append(deployed, *d)
+		return nil
+	}, nlp.WithTimeFormat("15:04"), nlp.WithTimeLocation(time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Learn(); err != nil {
+		t.Fatal(err)
+	}
+	r.HandleFallback(func(*CommandContext) error {
+		fallbacks++
+		return nil
+	})
+
+	send := func(text string) {
+		c := NewCommandContext(stdContext.Background(), client, &ciscospark.Message{
+			RoomID:   room.ID,
+			RoomType: "direct",
+			PersonID: alice.ID,
+			Text:     text,
+		})
+		c.store = store
+		if err := r.Dispatch(c); err != nil {
+			t.Fatalf("%q: %v", text, err)
+		}
+	}
+	lastReply := func() string {
+		messages := s.Messages(room.ID)
+		if len(messages) == 0 {
+			return ""
+		}
+		return messages[len(messages)-1].MarkDown
+	}
+	at := time.Date(0, 1, 1, 14, 30, 0, 0, time.UTC)
+	key := "person/" + alice.ID + "/intent/" + room.ID
+
+	send("deploy billing at 14:30")
+	if len(deployed) != 1 || deployed[0] != (deployIntent{"billing", at}) {
+		t.Fatalf("a complete message deployed %v", deployed)
+	}
+
+	// prompt and answer
+	send("deploy billing")
+	if got := lastReply(); got != "When? e.g. 14:30" {
+		t.Errorf("prompt = %q", got)
+	}
+	send("soon")
+	if got := lastReply(); got != "Sorry, that is not a valid answer. When? e.g. 14:30" {
+		t.Errorf("reply to an invalid answer = %q", got)
+	}
+	send(" 14:30 ")
+	if len(deployed) != 2 || deployed[1] != (deployIntent{"billing", at}) {
+		t.Fatalf("the answered intent deployed %v", deployed)
+	}
+	if ok, _ := store.Get(key, new(pendingIntent)); ok {
+		t.Error("the intent is still pending after its handler ran")
+	}
+
+	// cancel
+	send("deploy billing")
+	send("CANCEL")
+	if got := lastReply(); got != "OK, cancelled." {
+		t.Errorf("reply to cancel = %q", got)
+	}
+	send("14:30")
+	if len(deployed) != 2 || fallbacks != 1 {
+		t.Errorf("an answer after cancel deployed %d times and fell back %d times", len(deployed)-2, fallbacks)
+	}
+
+	// expiry
+	send("deploy billing")
+	store.mu.Lock()
+	entry := store.entries[key]
+	if d := time.Until(entry.Expires); d < pendingIntentTTL-time.Minute || d > pendingIntentTTL {
+		t.Errorf("pending intent expires in %v, want %v", d, pendingIntentTTL)
+	}
+	entry.Expires = time.Now().Add(-time.Second)
+	store.entries[key] = entry
+	store.mu.Unlock()
+	send("14:30")
+	if len(deployed) != 2 || fallbacks != 2 {
+		t.Errorf("an answer after expiry deployed %d times and fell back %d times", len(deployed)-2, fallbacks-1)
+	}
+}
@@ -0,0 +1,271 @@
+package app
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"../spark"
+	"github.com/kataras/iris"
+)
+
+// oauthStateTTL is how long the grant started by a login can be completed
+const oauthStateTTL = 10 * time.Minute
+
+var (
+	errOAuthAuthorized   = errors.New("the integration is already authorized, set spark.oauth.reauthorize to replace its token")
+	errOAuthUnknownState = errors.New("unknown or expired state")
+)
+
+// oauthIntegration completes the grant flow of a Spark integration and keeps
+// its tokens in a file outside the repository. Logins need the login secret
+// and start a grant whose state can be used once, a stored token is only
+// replaced when reauthorize is set.
+type oauthIntegration struct {
+	config      *ciscospark.OAuthConfig
+	source      *ciscospark.OAuthTokenSource
+	tokenFile   string
+	loginSecret string
+	generated   bool
+	reauthorize bool
+
+	mu         sync.Mutex
+	states     map[string]time.Time
+	authorized bool
+}
+
+// sparkTokenSource picks where the Spark token comes from: an OAuth
+// integration when spark.oauth.clientid is set, else the environment variable
+// named by spark.tokenenv, else spark.token. Tokens of the integration are
+// requested with client, the HTTP client of the API calls.
+func (a Application) sparkTokenSource(client *http.Client) (ciscospark.TokenSource, error) {
+	if clientID := a.conf.GetString("spark.oauth.clientid"); clientID != "" {
+		integration, err := a.newOAuthIntegration(clientID, client)
+		if err != nil {
+			return nil, err
+		}
+		New().oauth = integration
+		return integration.source, nil
+	}
+	if name := a.conf.GetString("spark.tokenenv"); name != "" && os.Getenv(name) != "" {
+		return ciscospark.EnvTokenSource(name), nil
+	}
+	if token := a.conf.GetString("spark.token"); token != "" {
+		a.Log.Warn("spark.token is read from the config file, prefer the ", a.conf.GetString("spark.tokenenv"), " environment variable")
+		return ciscospark.StaticTokenSource(token), nil
+	}
+	a.Log.Warn("no Spark token configured, set ", a.conf.GetString("spark.tokenenv"), " or spark.oauth.clientid")
+	return ciscospark.StaticTokenSource(""), nil
+}
+
+func (a Application) newOAuthIntegration(clientID string, client *http.Client) (*oauthIntegration, error) {
+	secret := a.conf.GetString("spark.oauth.clientsecret")
+	if env := os.Getenv("SPARK_CLIENT_SECRET"); env != "" {
+		secret = env
+	}
+	integration := &oauthIntegration{
+		config: &ciscospark.OAuthConfig{
+			ClientID:     clientID,
+			ClientSecret: secret,
+			RedirectURL:  a.conf.GetString("spark.oauth.redirecturl"),
+			Scopes:       strings.Fields(a.conf.GetString("spark.oauth.scopes")),
+			HTTPClient:   client,
+		},
+		tokenFile:   a.conf.GetString("spark.oauth.tokenfile"),
+		loginSecret: a.conf.GetString("spark.oauth.loginsecret"),
+		reauthorize: a.conf.GetBool("spark.oauth.reauthorize"),
+		states:      make(map[string]time.Time),
+	}
+	if integration.tokenFile == "" {
+		integration.tokenFile = filepath.Join(os.Getenv("HOME"), ".sparkbot", "token.json")
+	}
+	if integration.loginSecret == "" {
+		loginSecret, err := randomHex(16)
+		if err != nil {
+			return nil, err
+		}
+		integration.loginSecret, integration.generated = loginSecret, true
+	}
+
+	token, err := loadToken(integration.tokenFile)
+	if err != nil {
+		return nil, err
+	}
+	integration.authorized = token != nil
+	integration.source = ciscospark.NewOAuthTokenSource(integration.config, token)
+	integration.source.OnRefresh = func(token *ciscospark.Token) {
+		if err := saveToken(integration.tokenFile, token); err != nil {
+			New().Log.Error("OAUTH: ", err)
+		}
+	}
+	return integration, nil
+}
+
+// needsLogin reports whether the integration can be authorized through /oauth/login
+func (o *oauthIntegration) needsLogin() bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return !o.authorized || o.reauthorize
+}
+
+// hasToken reports whether the integration has been authorized
+func (o *oauthIntegration) hasToken() bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.authorized
+}
+
+// newState starts a grant and returns its state, valid once for oauthStateTTL
+func (o *oauthIntegration) newState() (string, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.authorized && !o.reauthorize {
+		return "", errOAuthAuthorized
+	}
+	state, err := randomHex(16)
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	for s, expiry := range o.states {
+		if now.After(expiry) {
+			delete(o.states, s)
+		}
+	}
+	o.states[state] = now.Add(oauthStateTTL)
+	return state, nil
+}
+
+// useState consumes state, failing when it was not issued, expired or already used
+func (o *oauthIntegration) useState(state string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.authorized && !o.reauthorize {
+		return errOAuthAuthorized
+	}
+	expiry, ok := o.states[state]
+	delete(o.states, state)
+	if !ok || time.Now().After(expiry) {
+		return errOAuthUnknownState
+	}
+	return nil
+}
+
+// store starts using token and saves it to the token file
+func (o *oauthIntegration) store(token *ciscospark.Token) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.authorized && !o.reauthorize {
+		return errOAuthAuthorized
+	}
+	o.source.SetToken(token)
+	o.authorized = true
+	return saveToken(o.tokenFile, token)
+}
+
+// loadToken reads a token saved by saveToken, a missing file is no token
+func loadToken(path string) (*ciscospark.Token, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	token := new(ciscospark.Token)
+	if err := json.Unmarshal(data, token); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// saveToken writes token to path, readable by the owner only
+func saveToken(path string, token *ciscospark.Token) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+// sparkbotOAuthLogin redirects to the Spark page granting the integration
+// access. It needs the login secret in the secret parameter.
+func sparkbotOAuthLogin(ctx iris.Context) {
+	a := New()
+	integration := a.oauth
+	if integration == nil {
+		ctx.StatusCode(iris.StatusNotFound)
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(ctx.URLParam("secret")), []byte(integration.loginSecret)) != 1 {
+		a.Log.Warn("OAUTH: rejected login without the login secret from ", ctx.RemoteAddr())
+		ctx.StatusCode(iris.StatusUnauthorized)
+		return
+	}
+	state, err := integration.newState()
+	if err == errOAuthAuthorized {
+		ctx.StatusCode(iris.StatusForbidden)
+		ctx.WriteString("The integration is already authorized.")
+		return
+	}
+	if err != nil {
+		a.Log.Error("OAUTH: ", err)
+		ctx.StatusCode(iris.StatusInternalServerError)
+		return
+	}
+	ctx.Redirect(integration.config.AuthCodeURL(state))
+}
+
+// sparkbotOAuthCallback exchanges the code Spark redirects back with for a
+// token and starts using it
+func sparkbotOAuthCallback(ctx iris.Context) {
+	a := New()
+	integration := a.oauth
+	if integration == nil {
+		ctx.StatusCode(iris.StatusNotFound)
+		return
+	}
+	if err := integration.useState(ctx.URLParam("state")); err != nil {
+		a.Log.Warn("OAUTH: rejected callback from ", ctx.RemoteAddr(), ": ", err)
+		if err == errOAuthAuthorized {
+			ctx.StatusCode(iris.StatusForbidden)
+		} else {
+			ctx.StatusCode(iris.StatusBadRequest)
+		}
+		return
+	}
+	if reason := ctx.URLParam("error"); reason != "" {
+		a.Log.Warn("OAUTH: authorization denied: ", reason)
+		ctx.StatusCode(iris.StatusForbidden)
+		ctx.WriteString("Authorization denied: " + reason)
+		return
+	}
+	token, err := integration.config.Exchange(ctx.Request().Context(), ctx.URLParam("code"))
+	if err != nil {
+		a.Log.Error("OAUTH: ", err)
+		ctx.StatusCode(iris.StatusBadGateway)
+		return
+	}
+	if err := integration.store(token); err != nil {
+		a.Log.Error("OAUTH: ", err)
+		ctx.StatusCode(iris.StatusInternalServerError)
+		return
+	}
+	a.Log.Info("OAUTH: integration authorized, token saved to ", integration.tokenFile)
+	if a.botID() == "" {
+		if err := a.resolveBot(ctx.Request().Context(), a.Spark); err != nil {
+			a.Log.Error("IDENTITY: ", err)
+		}
+	}
+	ctx.WriteString("Spark integration authorized.")
+}
@@ -0,0 +1,223 @@
+package app
+
+import (
+	stdContext "context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"../spark"
+	"github.com/Sirupsen/logrus"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	errQueueFull   = errors.New("event queue is full")
+	errQueueClosed = errors.New("event queue is not accepting events")
+)
+
+// queuedEvent is a webhook event waiting for a worker
+type queuedEvent struct {
+	body     []byte
+	event    *ciscospark.WebhookEvent
+	received time.Time
+}
+
+// eventProcessor handles a queued webhook event
+type eventProcessor func(stdContext.Context, *ciscospark.WebhookEvent) error
+
+// eventQueue processes webhook events on a bounded pool of workers, so the
+// callback can acknowledge events before the Spark API calls they need
+type eventQueue struct {
+	mu      sync.RWMutex
+	events  chan *queuedEvent
+	open    bool
+	wg      sync.WaitGroup
+	ctx     stdContext.Context
+	cancel  stdContext.CancelFunc
+	timeout time.Duration
+	process eventProcessor
+	failed  func(*queuedEvent, error)
+
+	busy     prometheus.Gauge
+	results  *prometheus.CounterVec
+	waitTime prometheus.Histogram
+}
+
+func newEventQueue() *eventQueue {
+	return &eventQueue{
+		busy: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "sparkbot_queue_busy_workers",
+			Help: "Number of workers processing a webhook event.",
+		}),
+		results: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "sparkbot_queue_events_total",
+			Help: "Webhook events by outcome: processed, failed or rejected when the queue was full or closed.",
+		}, []string{"result"}),
+		waitTime: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "sparkbot_queue_wait_seconds",
+			Help:    "Time webhook events spent queued before a worker picked them up.",
+			Buckets: []float64{.01, .05, .1, .5, 1, 5, 10, 30},
+		}),
+	}
+}
+
+// register exposes the metrics of the queue on the default prometheus registry
+func (q *eventQueue) register() {
+	prometheus.MustRegister(q.busy, q.results, q.waitTime,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "sparkbot_queue_depth",
+			Help: "Number of webhook events waiting for a worker.",
+		}, q.depth),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "sparkbot_queue_capacity",
+			Help: "Number of webhook events the queue holds before rejecting new ones.",
+		}, q.capacity),
+	)
+}
+
+func (q *eventQueue) depth() float64 {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	return float64(len(q.events))
+}
+
+func (q *eventQueue) capacity() float64 {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	return float64(cap(q.events))
+}
+
+// start runs workers goroutines processing up to depth queued events, each
+// within timeout. Events that fail are passed to failed.
+func (q *eventQueue) start(workers, depth int, timeout time.Duration, process eventProcessor, failed func(*queuedEvent, error)) {
+	if workers < 1 {
+		workers = 1
+	}
+	if depth < 0 {
+		depth = 0
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.events = make(chan *queuedEvent, depth)
+	q.ctx, q.cancel = stdContext.WithCancel(stdContext.Background())
+	q.timeout, q.process, q.failed = timeout, process, failed
+	q.open = true
+	for i := 0; i < workers; i++ {
+		q.wg.Add(1)
+		go q.work(q.events)
+	}
+}
+
+// Enqueue queues an event without waiting, failing when the queue is full
+// or not started
+func (q *eventQueue) Enqueue(body []byte, event *ciscospark.WebhookEvent) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if !q.open {
+		q.results.WithLabelValues("rejected").Inc()
+		return errQueueClosed
+	}
+	select {
+	case q.events <- &queuedEvent{body: body, event: event, received: time.Now()}:
+		return nil
+	default:
+		q.results.WithLabelValues("rejected").Inc()
+		return errQueueFull
+	}
+}
+
+func (q *eventQueue) work(events <-chan *queuedEvent) {
+	defer q.wg.Done()
+	for e := range events {
+		q.waitTime.Observe(time.Since(e.received).Seconds())
+		q.busy.Inc()
+		err := q.run(e)
+		q.busy.Dec()
+		if err != nil {
+			q.results.WithLabelValues("failed").Inc()
+			q.failed(e, err)
+			continue
+		}
+		q.results.WithLabelValues("processed").Inc()
+	}
+}
+
+// run processes an event, turning a panic of its handler into an error
+func (q *eventQueue) run(e *queuedEvent) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	ctx, cancel := stdContext.WithTimeout(q.ctx, q.timeout)
+	defer cancel()
+	return q.process(ctx, e.event)
+}
+
+// drain stops accepting events and waits for the queued ones to be processed.
+// Once timeout elapses the events still running are cancelled.
+func (q *eventQueue) drain(timeout time.Duration) error {
+	q.mu.Lock()
+	if !q.open {
+		q.mu.Unlock()
+		return nil
+	}
+	q.open = false
+	close(q.events)
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		q.cancel()
+		return nil
+	case <-time.After(timeout):
+		q.cancel()
+		return fmt.Errorf("event queue not drained after %v, %v events left", timeout, q.depth())
+	}
+}
+
+// startQueue starts the workers processing webhook events with the
+// spark.queue.* settings
+func (a Application) startQueue() error {
+	deadLetters := a.Log
+	if path := a.conf.GetString("spark.queue.deadletter"); path != "" {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return err
+		}
+		deadLetters = logrus.New()
+		deadLetters.Out = f
+		deadLetters.Formatter = &logrus.JSONFormatter{}
+	}
+	a.queue.start(
+		a.conf.GetInt("spark.queue.workers"),
+		a.conf.GetInt("spark.queue.depth"),
+		time.Duration(a.conf.GetInt("spark.queue.timeout"))*time.Second,
+		a.processEvent,
+		func(e *queuedEvent, err error) {
+			deadLetters.WithFields(logrus.Fields{
+				"resource": e.event.Resource,
+				"event":    e.event.Event,
+				"id":       e.event.DataID(),
+				"body":     string(e.body),
+			}).Error("DEADLETTER: ", err)
+		},
+	)
+	return nil
+}
+
+// processEvent runs the handler of a queued webhook event
+func (a Application) processEvent(ctx stdContext.Context, event *ciscospark.WebhookEvent) error {
+	if a.ignoreEvent(event) {
+		return nil
+	}
+	return a.Events.Dispatch(NewEventContext(ctx, a.Spark, event))
+}
@@ -0,0 +1,216 @@
+package app
+
+import (
+	stdContext "context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"../spark"
+	"github.com/kataras/iris"
+)
+
+func testEvent(id string) *ciscospark.WebhookEvent {
+	return &ciscospark.WebhookEvent{
+		Resource: ciscospark.ResourceMessages,
+		Event:    ciscospark.EventCreated,
+		Data:     &ciscospark.Message{ID: id},
+	}
+}
+
+func TestEventQueue_Full(t *testing.T) {
+	q := newEventQueue()
+	started, release := make(chan struct{}, 1), make(chan struct{})
+	q.start(1, 1, time.Second, func(ctx stdContext.Context, event *ciscospark.WebhookEvent) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}, func(e *queuedEvent, err error) {
+		t.Errorf("event %s failed: %v", e.event.DataID(), err)
+	})
+	defer q.drain(time.Second)
+	defer close(release)
+
+	if err := q.Enqueue(nil, testEvent("1")); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+	if err := q.Enqueue(nil, testEvent("2")); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Enqueue(nil, testEvent("3")); err != errQueueFull {
+		t.Errorf("Enqueue on a full queue: error = %v, want %v", err, errQueueFull)
+	}
+
+	app := iris.New()
+	app.Post("/callback", sparkbotCallback(q))
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(app)
+	defer srv.Close()
+	resp, err := http.Post(srv.URL+"/callback", "application/json", strings.NewReader(`{"resource":"messages","event":"created","data":{"id":"4"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("callback on a full queue: status = %d, want 503", resp.StatusCode)
+	}
+}
+
+func TestEventQueue_Closed(t *testing.T) {
+	q := newEventQueue()
+	if err := q.Enqueue(nil, testEvent("1")); err != errQueueClosed {
+		t.Errorf("Enqueue before start: error = %v, want %v", err, errQueueClosed)
+	}
+	q.start(1, 1, time.Second, func(stdContext.Context, *ciscospark.WebhookEvent) error { return nil }, nil)
+	if err := q.drain(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Enqueue(nil, testEvent("2")); err != errQueueClosed {
+		t.Errorf("Enqueue after drain: error = %v, want %v", err, errQueueClosed)
+	}
+}
+
+func TestEventQueue_Drain(t *testing.T) {
+	q := newEventQueue()
+	processed := make(chan string, 3)
+	q.start(1, 3, time.Second, func(ctx stdContext.Context, event *ciscospark.WebhookEvent) error {
+		time.Sleep(10 * time.Millisecond)
+		processed <- event.DataID()
+		return nil
+	}, nil)
+	for _, id := range []string{"1", "2", "3"} {
+		if err := q.Enqueue(nil, testEvent(id)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := q.drain(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if len(processed) != 3 {
+		t.Errorf("%d of 3 queued events were processed before drain returned", len(processed))
+	}
+}
+
+func TestEventQueue_DrainTimeout(t *testing.T) {
+	q := newEventQueue()
+	cancelled := make(chan error, 1)
+	q.start(1, 1, time.Minute, func(ctx stdContext.Context, event *ciscospark.WebhookEvent) error {
+		<-ctx.Done()
+		cancelled <- ctx.Err()
+		return ctx.Err()
+	}, func(*queuedEvent, error) {})
+	if err := q.Enqueue(nil, testEvent("1")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := q.drain(20 * time.Millisecond); err == nil {
+		t.Error("drain returned no error while an event was still running")
+	}
+	select {
+	case err := <-cancelled:
+		if err != stdContext.Canceled {
+			t.Errorf("running event ended with %v, want %v", err, stdContext.Canceled)
+		}
+	case <-time.After(time.Second):
+		t.Error("the running event was not cancelled after the drain timeout")
+	}
+}
+
+func TestEventQueue_Panic(t *testing.T) {
+	q := newEventQueue()
+	failed := make(chan error, 1)
+	processed := make(chan string, 1)
+	q.start(1, 2, time.Second, func(ctx stdContext.Context, event *ciscospark.WebhookEvent) error {
+		if event.DataID() == "panics" {
+			panic("boom")
+		}
+		processed <- event.DataID()
+		return nil
+	}, func(e *queuedEvent, err error) {
+		failed <- err
+	})
+	for _, id := range []string{"panics", "after"} {
+		if err := q.Enqueue(nil, testEvent(id)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := q.drain(time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-failed:
+		if err == nil || !strings.Contains(err.Error(), "panic: boom") {
+			t.Errorf("failed with %v, want the panic", err)
+		}
+	default:
+		t.Error("the panicking event was not reported as failed")
+	}
+	select {
+	case id := <-processed:
+		if id != "after" {
+			t.Errorf("processed %q", id)
+		}
+	default:
+		t.Error("the worker stopped after a panic")
+	}
+}
+
+func TestStartQueue_DeadLetters(t *testing.T) {
+	a := New()
+	a.botMu.Lock()
+	bot := *a.bot
+	a.bot.ID = "dead-letter-bot"
+	a.botMu.Unlock()
+	defer func() {
+		a.botMu.Lock()
+		*a.bot = bot
+		a.botMu.Unlock()
+	}()
+	a.Events.Handle("deadLetterTest", ciscospark.EventCreated, func(*EventContext) error {
+		return errors.New("handler failed")
+	})
+	a.seen = newSeenSet()
+	path := filepath.Join(t.TempDir(), "deadletter.log")
+	a.conf.Set("spark.queue.deadletter", path)
+	defer a.conf.Set("spark.queue.deadletter", "")
+
+	if err := a.startQueue(); err != nil {
+		t.Fatal(err)
+	}
+	body := []byte(`{"resource":"deadLetterTest","event":"created","data":{"id":"d1"}}`)
+	event, err := ciscospark.ParseWebhookEvent(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.queue.Enqueue(body, event); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.queue.drain(time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entry map[string]interface{}
+	if err := json.Unmarshal(data, &entry); err != nil {
+		t.Fatalf("dead letter %q is not JSON: %v", data, err)
+	}
+	if entry["resource"] != "deadLetterTest" || entry["event"] != "created" || entry["body"] != string(body) {
+		t.Errorf("dead letter = %s", data)
+	}
+	if msg, _ := entry["msg"].(string); !strings.Contains(msg, "handler failed") {
+		t.Errorf("dead letter message = %q, want the handler error", msg)
+	}
+}
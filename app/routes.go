@@ -1,189 +1,265 @@
 package app
 
 import (
-	"crypto/tls"
-	"encoding/json"
+	stdContext "context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io/ioutil"
-	"log"
-	"net/http"
+	"time"
 
 	"../spark"
 	"github.com/kataras/iris"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
-type DataStruct struct {
-	MessageID   string `json:"id"`
-	RoomType    string `json:"roomType"`
-	PersonID    string `json:"personId"`
-	PersonEmail string `json:"personEmail"`
-	Created     string `json:"created"`
-}
-
-type Message struct {
-	ID        string     `json:"id"`
-	Name      string     `json:"name"`
-	TargetURL string     `json:"targetUrl"`
-	Resource  string     `json:"resource"`
-	Event     string     `json:"event"`
-	Filter    string     `json:"filter"`
-	OrgID     string     `json:"orgId"`
-	CreatedBy string     `json:"createdBy"`
-	AppID     string     `json:"appId"`
-	OwnedBy   string     `json:"ownedBy"`
-	Status    string     `json:"status"`
-	Created   string     `json:"created"`
-	ActorID   string     `json:"actorId"`
-	Data      DataStruct `json:"data"`
-}
-
 func (a Application) addRoutes() {
-	a.Server.Post("/callback", sparkbotCallback)
-	a.Server.Get("/help", sparkbotHelp)
-	a.Server.Get("/fallback", sparkbotFallback)
-	a.Server.Get("/hello", sparkbotHello)
-	a.Server.Get("/about", sparkbotAbout)
+	a.Server.Post("/callback", sparkbotVerifySignature, sparkbotCallback(a.queue))
+	a.Server.Get("/oauth/login", sparkbotOAuthLogin)
+	a.Server.Get("/oauth/callback", sparkbotOAuthCallback)
 	a.Server.Get("/metrics", iris.FromStd(prometheus.Handler()))
 }
 
-func sparkbotCallback(ctx iris.Context) {
-	body, err := ioutil.ReadAll(ctx.Request().Body)
-	if err != nil {
-		panic(err)
-	}
-	var mess Message
-	err = json.Unmarshal(body, &mess)
-	/*
-
-		{"id":"Y2lzY29zcGFyazovL3VzL1dFQkhPT0svZWFhZWE1MjYtNzMxZi00OWIxLWIyYzEtNTFkOWY5NGQ1ZDY2",
-			"name":"MyTestHook",
-			"targetUrl":"https://roporter1234.localtunnel.me/callback",
-			"resource":"messages",
-			"event":"created",
-			"filter":"roomId=Y2lzY29zcGFyazovL3VzL1JPT00vOGMyYWFkMTAtYTE0Mi0xMWU3LThmYzEtMWY5YWY0Y2EwOTNm",
-			"orgId":"Y2lzY29zcGFyazovL3VzL09SR0FOSVpBVElPTi8xZWI2NWZkZi05NjQzLTQxN2YtOTk3NC1hZDcyY2FlMGUxMGY",
-			"createdBy":"Y2lzY29zcGFyazovL3VzL1BFT1BMRS82YjdjY2ZmNy04ZWRhLTRkMTYtOWExZS0yNzQ0MDYwZDU0ODQ",
-			"appId":"Y2lzY29zcGFyazovL3VzL0FQUExJQ0FUSU9OL0MyNzljYjMwYzAyOTE4MGJiNGJkYWViYjA2MWI3OTY1Y2RhMzliNjAyOTdjODUwM2YyNjZhYmY2NmM5OTllYzFm",
-			"ownedBy":"creator",
-			"status":"active",
-			"created":"2017-09-25T10:13:14.729Z",
-			"actorId":"Y2lzY29zcGFyazovL3VzL1BFT1BMRS82YjdjY2ZmNy04ZWRhLTRkMTYtOWExZS0yNzQ0MDYwZDU0ODQ",
-			"data":{"id":"Y2lzY29zcGFyazovL3VzL01FU1NBR0UvMDhjNmIzMTAtYTFlMi0xMWU3LWFlN2QtY2Y4YzY2Nzg4NWU2",
-				"roomId":"Y2lzY29zcGFyazovL3VzL1JPT00vOGMyYWFkMTAtYTE0Mi0xMWU3LThmYzEtMWY5YWY0Y2EwOTNm",
-				"roomType":"group",
-				"personId":"Y2lzY29zcGFyazovL3VzL1BFT1BMRS82YjdjY2ZmNy04ZWRhLTRkMTYtOWExZS0yNzQ0MDYwZDU0ODQ",
-				"personEmail":"roporter@cisco.com",
-				"created":"2017-09-25T11:09:44.001Z"}}
-
-	*/
-	message := getMessageContent(mess.Data.MessageID)
-	fmt.Println(message)
-	if message == "about" {
-		sparkbotAbout(ctx)
-	}
-}
-
-func getMessageContent(data string) string {
+// sparkbotVerifySignature rejects callbacks that are not signed with spark.secret.
+// Without a secret every callback is rejected.
+func sparkbotVerifySignature(ctx iris.Context) {
 	a := New()
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-	}
-	client := &http.Client{Transport: tr}
-	sparkClient := ciscospark.NewClient(client)
-	token := a.conf.GetString("spark.token")
-	sparkClient.Authorization = "Bearer " + token
-	htmlMessageGet, _, err := sparkClient.Messages.GetMessage(data)
+	secret := a.conf.GetString("spark.secret")
+	if secret == "" {
+		a.Log.Error("WEBHOOK: rejected callback from ", ctx.RemoteAddr(), ": spark.secret is not set")
+		ctx.StatusCode(iris.StatusUnauthorized)
+		ctx.StopExecution()
+		return
+	}
+	_, err := ciscospark.VerifyWebhookRequest(ctx.Request(), secret)
 	if err != nil {
-		log.Fatal(err)
+		a.Log.Warn("WEBHOOK: rejected callback from ", ctx.RemoteAddr(), ": ", err)
+		ctx.StatusCode(iris.StatusUnauthorized)
+		ctx.StopExecution()
+		return
 	}
-	a.Log.Info("GET <ID>:", htmlMessageGet.ID, htmlMessageGet.Text, htmlMessageGet.Created)
-	return htmlMessageGet.Text
+	ctx.Next()
 }
 
-func sparkbotHelp(ctx iris.Context) {
-	sendSparkMessage("Hi, I am the Hello World bot !\n\nType /hello to see me in action.")
+// randomHex returns n random bytes encoded as hex
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
 }
 
-func sparkbotFallback(ctx iris.Context) {
-	sendSparkMessage("Sorry, I did not understand.\n\nTry /help.")
+// sparkbotCallback queues webhook events and acknowledges them at once. Spark
+// is asked to redeliver events when the queue is full.
+func sparkbotCallback(queue *eventQueue) iris.Handler {
+	return func(ctx iris.Context) {
+		a := New()
+		body, err := ioutil.ReadAll(ctx.Request().Body)
+		if err != nil {
+			a.Log.Error(err)
+			ctx.StatusCode(iris.StatusBadRequest)
+			return
+		}
+		event, err := ciscospark.ParseWebhookEvent(body)
+		if err != nil {
+			a.Log.Error(err)
+			ctx.StatusCode(iris.StatusBadRequest)
+			return
+		}
+		if err := queue.Enqueue(body, event); err != nil {
+			a.Log.Warn("WEBHOOK: ", event.Resource, " ", event.Event, " not queued: ", err)
+			ctx.StatusCode(iris.StatusServiceUnavailable)
+			return
+		}
+		ctx.StatusCode(iris.StatusOK)
+	}
 }
 
-func sparkbotHello(ctx iris.Context) {
-	sendSparkMessage("Hello <@personEmail:roporter@cisco.com>")
+// ignoreEvent reports whether an event was caused by the bot itself or was
+// already processed. Events are told apart by resource, event and data ID, so
+// the update of a message is not mistaken for its creation. Every event is
+// ignored while the bot identity is unknown, so the bot never answers itself.
+func (a Application) ignoreEvent(event *ciscospark.WebhookEvent) bool {
+	botID := a.botID()
+	if botID == "" {
+		a.Log.Warn("WEBHOOK: dropping ", event.Resource, " ", event.Event, ", the bot identity is not known yet")
+		return true
+	}
+	if personID := event.PersonID(); personID != "" && personID == botID {
+		a.Log.Debug("WEBHOOK: ignoring ", event.Resource, " ", event.Event, " by the bot")
+		return true
+	}
+	if event.DataID() == "" {
+		return false
+	}
+	seen, err := a.seen.Seen(event.Resource + ":" + event.Event + ":" + event.DataID())
+	if err != nil {
+		a.Log.Error("DEDUPE: ", err)
+	}
+	if seen {
+		a.Log.Debug("WEBHOOK: ignoring redelivered ", event.Resource, " ", event.Event, " ", event.DataID())
+	}
+	return seen
+}
+
+// botID returns the person ID of the bot, or "" until resolveBot succeeded
+func (a Application) botID() string {
+	a.botMu.Lock()
+	defer a.botMu.Unlock()
+	return a.bot.ID
 }
 
-func sparkbotAbout(ctx iris.Context) {
-	sendSparkMessage("```\n{\n   'author':'Robert Porter <roporter@cisco.com>',\n   'code':'https://github.com/robjporter/go-sparkbot',\n   'description':'A handy tool to interact with Cisco Spark.',\n}```")
+// resolveBot looks up the identity of the bot and the names it is mentioned
+// by. Failed API calls are already retried by the client, so only network
+// errors are tried again, up to spark.identity.attempts times.
+func (a Application) resolveBot(ctx stdContext.Context, sparkClient *ciscospark.Client) error {
+	delay := time.Second
+	for attempt := 1; ; attempt++ {
+		me, err := getSparkMe(ctx, sparkClient)
+		if err == nil {
+			a.botMu.Lock()
+			*a.bot = *me
+			a.botMu.Unlock()
+			a.Commands.SetMentionNames(me.DisplayName, me.NickName, me.FirstName)
+			a.Log.Info("IDENTITY: running as ", me.DisplayName, " (", me.ID, ")")
+			return nil
+		}
+		var apiErr *ciscospark.ErrorResponse
+		if errors.As(err, &apiErr) || attempt >= a.conf.GetInt("spark.identity.attempts") {
+			return fmt.Errorf("can't resolve the bot identity: %v", err)
+		}
+		a.Log.Warn("IDENTITY: ", err, ", retrying in ", delay)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		delay *= 2
+	}
 }
 
-func deleteWebHooks() {
+func (a Application) addEvents() {
+	a.Events.Handle(ciscospark.ResourceMessages, ciscospark.EventCreated, messageCreated)
+	a.Events.Handle(ciscospark.ResourceAttachmentActions, ciscospark.EventCreated, cardSubmitted)
+	a.Events.Handle(ciscospark.ResourceMemberships, ciscospark.EventCreated, memberAdded)
+}
+
+// messageCreated fetches the text of a new message and runs its command
+func messageCreated(c *EventContext) error {
 	a := New()
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	message, err := getSparkMessage(c.Context(), c.Spark, c.Message.ID)
+	if err != nil {
+		return err
+	}
+	return a.Commands.Dispatch(NewCommandContext(c.Context(), c.Spark, message))
+}
+
+// cardSubmitted fetches the inputs of a card submission and runs its card handler
+func cardSubmitted(c *EventContext) error {
+	data, _ := c.Event.AttachmentAction()
+	action, _, err := c.Spark.AttachmentActions.GetAttachmentAction(c.Context(), data.ID)
+	if err != nil {
+		return err
 	}
-	client := &http.Client{Transport: tr}
-	sparkClient := ciscospark.NewClient(client)
-	token := a.conf.GetString("spark.token")
-	sparkClient.Authorization = "Bearer " + token
-	webhooksQueryParams := &ciscospark.WebhookQueryParams{
-		Max: 10,
+	return New().Cards.Dispatch(NewCardContext(c.Context(), c.Spark, action))
+}
+
+// memberAdded greets people added to a room the bot is in
+func memberAdded(c *EventContext) error {
+	a := New()
+	if c.PersonID == a.botID() || c.RoomType == "direct" {
+		return nil
 	}
-	webhooks, _, err := sparkClient.Webhooks.Get(webhooksQueryParams)
+	return c.Reply("Welcome <@personEmail:" + c.PersonEmail + ">!\n\n" + helpText)
+}
+
+func (a Application) addCommands() {
+	a.Commands.Handle("/help", helpCommand)
+	a.Commands.Handle("/hello", helloCommand)
+	a.Commands.Handle("/about", aboutCommand)
+	a.Commands.Handle("about", aboutCommand)
+	a.Commands.HandleFallback(a.Intents.Dispatch)
+}
+
+// RemindIntent is filled from messages such as "remind me to deploy in 2h"
+type RemindIntent struct {
+	Task string        `nlp:"required,prompt=What should I remind you about?"`
+	In   time.Duration `nlp:"required,prompt=In how long? e.g. 30m or 2h"`
+}
+
+func (a Application) addIntents() {
+	err := a.Intents.Register(RemindIntent{}, []string{
+		"remind me to {Task} in {In}",
+		"remind me in {In} to {Task}",
+		"remind me to {Task}",
+	}, remindIntent)
 	if err != nil {
-		log.Fatal(err)
+		a.Log.Error(err)
+	}
+	if err := a.Intents.Learn(); err != nil {
+		a.Log.Error(err)
 	}
-	for _, webhook := range webhooks {
-		resp, err := sparkClient.Webhooks.DeleteWebhook(webhook.ID)
+	a.Intents.HandleFallback(fallbackCommand)
+}
+
+func remindIntent(c *CommandContext, r *RemindIntent) error {
+	if r.Task == "" || r.In <= 0 {
+		return c.Reply("Try: remind me to deploy in 2h")
+	}
+	roomID, sparkClient := c.RoomID, c.Spark
+	time.AfterFunc(r.In, func() {
+		_, err := postSparkMessage(stdContext.Background(), sparkClient, &ciscospark.MessageRequest{
+			RoomID:   roomID,
+			MarkDown: "Reminder: " + r.Task,
+		})
 		if err != nil {
-			log.Fatal(err)
+			New().Log.Error(err)
 		}
-		fmt.Println("DELETE:", resp.StatusCode)
-	}
+	})
+	return c.Reply("OK, I will remind you to " + r.Task + " in " + r.In.String() + ".")
 }
 
-func registerWebHook() {
+const (
+	helpText     = "Hi, I am the Hello World bot !\n\nType /hello to see me in action."
+	fallbackText = "Sorry, I did not understand.\n\nTry /help."
+	aboutText    = "```\n{\n   'author':'Robert Porter <roporter@cisco.com>',\n   'code':'https://github.com/robjporter/go-sparkbot',\n   'description':'A handy tool to interact with Cisco Spark.',\n}```"
+)
+
+func helpCommand(c *CommandContext) error     { return c.Reply(helpText) }
+func fallbackCommand(c *CommandContext) error { return c.Reply(fallbackText) }
+func aboutCommand(c *CommandContext) error    { return c.Reply(aboutText) }
+func helloCommand(c *CommandContext) error {
+	return c.Reply("Hello <@personEmail:" + c.PersonEmail + ">")
+}
+
+func getSparkMessage(ctx stdContext.Context, sparkClient *ciscospark.Client, messageID string) (*ciscospark.Message, error) {
 	a := New()
-	myRoomID := a.conf.GetString("spark.roomid")
-	a.Log.Info("WEBHOOK: Registering a new WebHook for Room ID: ", myRoomID)
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-	}
-	client := &http.Client{Transport: tr}
-	sparkClient := ciscospark.NewClient(client)
-	token := a.conf.GetString("spark.token")
-	sparkClient.Authorization = "Bearer " + token
-	webHookURL := "https://roporter1234.localtunnel.me"
-	webhookRequest := &ciscospark.WebhookRequest{
-		Name:      a.conf.GetString("spark.hookname"),
-		TargetURL: webHookURL,
-		Resource:  "messages",
-		Event:     "created",
-		Filter:    "roomId=" + myRoomID,
-	}
-	testWebhook, _, err := sparkClient.Webhooks.Post(webhookRequest)
+	htmlMessageGet, _, err := sparkClient.Messages.GetMessage(ctx, messageID)
 	if err != nil {
-		a.Log.Error(err)
+		return nil, err
 	}
-	a.Log.Info("POST:", testWebhook.ID, testWebhook.Name, testWebhook.TargetURL, testWebhook.Created)
+	a.Log.Info("GET <ID>:", htmlMessageGet.ID, htmlMessageGet.Text, htmlMessageGet.Created)
+	return htmlMessageGet, nil
+}
 
+func getSparkMe(ctx stdContext.Context, sparkClient *ciscospark.Client) (*ciscospark.Person, error) {
+	me, _, err := sparkClient.People.GetMe(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return me, nil
 }
 
-func getSparkMessages(count int) {
+func getSparkMessages(ctx stdContext.Context, sparkClient *ciscospark.Client, count int) {
 	a := New()
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-	}
-	client := &http.Client{Transport: tr}
-	sparkClient := ciscospark.NewClient(client)
-	token := a.conf.GetString("spark.token")
-	sparkClient.Authorization = "Bearer " + token
 	myRoomID := a.conf.GetString("spark.roomid")
 	messageQueryParams := &ciscospark.MessageQueryParams{
 		Max:    count,
 		RoomID: myRoomID,
 	}
-	messages, _, err := sparkClient.Messages.Get(messageQueryParams)
+	messages, _, err := sparkClient.Messages.Get(ctx, messageQueryParams)
 	if err != nil {
 		a.Log.Error(err)
 	}
@@ -192,24 +268,12 @@ func getSparkMessages(count int) {
 	}
 }
 
-func sendSparkMessage(mess string) {
-	//getSparkMessages(1)
+func postSparkMessage(ctx stdContext.Context, sparkClient *ciscospark.Client, htmlMessage *ciscospark.MessageRequest) (*ciscospark.Message, error) {
 	a := New()
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-	}
-	client := &http.Client{Transport: tr}
-	sparkClient := ciscospark.NewClient(client)
-	token := a.conf.GetString("spark.token")
-	sparkClient.Authorization = "Bearer " + token
-	myRoomID := a.conf.GetString("spark.roomid")
-	htmlMessage := &ciscospark.MessageRequest{
-		MarkDown: mess,
-		RoomID:   myRoomID,
-	}
-	newHTMLMessage, _, err := sparkClient.Messages.Post(htmlMessage)
+	newHTMLMessage, _, err := sparkClient.Messages.Post(ctx, htmlMessage)
 	if err != nil {
-		a.Log.Error(err)
+		return nil, err
 	}
 	a.Log.Info("POST:", newHTMLMessage.ID, newHTMLMessage.MarkDown, newHTMLMessage.Created)
+	return newHTMLMessage, nil
 }
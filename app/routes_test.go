@@ -0,0 +1,54 @@
+package app
+
+import (
+	stdContext "context"
+	"testing"
+
+	"../spark"
+	"../spark/sparktest"
+)
+
+func TestResolveBot(t *testing.T) {
+	s := sparktest.NewServer(nil)
+	defer s.Close()
+	ctx := stdContext.Background()
+	a := New()
+	a.seen = newSeenSet()
+	*a.bot = ciscospark.Person{}
+
+	fromAlice := &ciscospark.WebhookEvent{
+		Resource: ciscospark.ResourceMessages,
+		Event:    ciscospark.EventCreated,
+		Data:     &ciscospark.Message{ID: "resolve-bot-1", PersonID: "alice"},
+	}
+	if !a.ignoreEvent(fromAlice) {
+		t.Error("an event was processed before the bot identity was known")
+	}
+
+	if err := a.resolveBot(ctx, s.Client()); err != nil {
+		t.Fatal(err)
+	}
+	if got := a.botID(); got != s.Me().ID {
+		t.Errorf("botID = %q, want %q", got, s.Me().ID)
+	}
+	fromBot := &ciscospark.WebhookEvent{
+		Resource: ciscospark.ResourceMessages,
+		Event:    ciscospark.EventCreated,
+		Data:     &ciscospark.Message{ID: "resolve-bot-2", PersonID: s.Me().ID},
+	}
+	if !a.ignoreEvent(fromBot) {
+		t.Error("an event caused by the bot was not ignored")
+	}
+	if a.ignoreEvent(fromAlice) {
+		t.Error("an event from alice was ignored once the bot identity was known")
+	}
+
+	s.RevokeToken("sparktest")
+	*a.bot = ciscospark.Person{}
+	if err := a.resolveBot(ctx, s.Client()); err == nil {
+		t.Error("resolveBot succeeded with a revoked token")
+	}
+	if got := a.botID(); got != "" {
+		t.Errorf("botID = %q after a failed lookup", got)
+	}
+}
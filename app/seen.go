@@ -0,0 +1,78 @@
+package app
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+// seenSet remembers the webhook events already processed for a while, so
+// that redelivered events are dropped. When a path is set the set is saved
+// after every new event and loaded on start, surviving quick restarts.
+type seenSet struct {
+	mu   sync.Mutex
+	ttl  time.Duration
+	path string
+	seen map[string]time.Time
+}
+
+func newSeenSet() *seenSet {
+	return &seenSet{ttl: 10 * time.Minute, seen: make(map[string]time.Time)}
+}
+
+// configure sets the TTL and the file the set is persisted to, loading the
+// events it holds. An empty path keeps the set in memory only.
+func (s *seenSet) configure(ttl time.Duration, path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ttl, s.path = ttl, path
+	if path == "" {
+		return nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &s.seen); err != nil {
+		return err
+	}
+	s.prune(time.Now())
+	return nil
+}
+
+// Seen records key and reports whether it was already recorded within the TTL
+func (s *seenSet) Seen(key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	s.prune(now)
+	if _, ok := s.seen[key]; ok {
+		return true, nil
+	}
+	s.seen[key] = now
+	return false, s.save()
+}
+
+func (s *seenSet) prune(now time.Time) {
+	for key, at := range s.seen {
+		if now.Sub(at) > s.ttl {
+			delete(s.seen, key)
+		}
+	}
+}
+
+func (s *seenSet) save() error {
+	if s.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(s.seen)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.path, data)
+}
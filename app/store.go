@@ -0,0 +1,187 @@
+package app
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Store holds conversation state. Values are stored as JSON and expire after
+// their TTL, a TTL of zero keeps them until deleted.
+type Store interface {
+	// Get decodes the value of key into v and reports whether it was found
+	Get(key string, v interface{}) (bool, error)
+	Set(key string, v interface{}, ttl time.Duration) error
+	Delete(key string) error
+}
+
+// State is the part of a Store holding the keys of one room or person
+type State struct {
+	store  Store
+	prefix string
+}
+
+// Get decodes the value of key into v and reports whether it was found
+func (s State) Get(key string, v interface{}) (bool, error) {
+	return s.store.Get(s.prefix+key, v)
+}
+
+// Set stores v under key for ttl
+func (s State) Set(key string, v interface{}, ttl time.Duration) error {
+	return s.store.Set(s.prefix+key, v, ttl)
+}
+
+// Delete removes key
+func (s State) Delete(key string) error {
+	return s.store.Delete(s.prefix + key)
+}
+
+// RoomState returns the state of the room the command came from
+func (c *CommandContext) RoomState() State {
+	return State{store: c.store, prefix: "room/" + c.RoomID + "/"}
+}
+
+// PersonState returns the state of the person who sent the command
+func (c *CommandContext) PersonState() State {
+	return State{store: c.store, prefix: "person/" + c.PersonID + "/"}
+}
+
+type storeEntry struct {
+	Value   json.RawMessage `json:"value"`
+	Expires time.Time       `json:"expires,omitempty"`
+}
+
+func (e storeEntry) expired(now time.Time) bool {
+	return !e.Expires.IsZero() && now.After(e.Expires)
+}
+
+// MemoryStore is a Store lost when the bot stops
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]storeEntry
+}
+
+// NewMemoryStore returns an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]storeEntry)}
+}
+
+// Get decodes the value of key into v and reports whether it was found
+func (s *MemoryStore) Get(key string, v interface{}) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[key]
+	if !ok || e.expired(time.Now()) {
+		return false, nil
+	}
+	return true, json.Unmarshal(e.Value, v)
+}
+
+// Set stores v under key for ttl
+func (s *MemoryStore) Set(key string, v interface{}, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.set(key, v, ttl)
+}
+
+// Delete removes key
+func (s *MemoryStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+	return nil
+}
+
+func (s *MemoryStore) set(key string, v interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	s.prune(now)
+	e := storeEntry{Value: data}
+	if ttl > 0 {
+		e.Expires = now.Add(ttl)
+	}
+	s.entries[key] = e
+	return nil
+}
+
+func (s *MemoryStore) prune(now time.Time) {
+	for key, e := range s.entries {
+		if e.expired(now) {
+			delete(s.entries, key)
+		}
+	}
+}
+
+// FileStore is a Store saved to a JSON file after every change
+type FileStore struct {
+	MemoryStore
+	path string
+}
+
+// NewFileStore returns a FileStore saved to path, loading the state it holds
+func NewFileStore(path string) (*FileStore, error) {
+	s := &FileStore{MemoryStore: *NewMemoryStore(), path: path}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &s.entries); err != nil {
+		return nil, err
+	}
+	s.prune(time.Now())
+	return s, nil
+}
+
+// Set stores v under key for ttl
+func (s *FileStore) Set(key string, v interface{}, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.set(key, v, ttl); err != nil {
+		return err
+	}
+	return s.save()
+}
+
+// Delete removes key
+func (s *FileStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+	return s.save()
+}
+
+func (s *FileStore) save() error {
+	data, err := json.Marshal(s.entries)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.path, data)
+}
+
+// writeFileAtomic writes data to a temporary file renamed over path, so a
+// crash never leaves a truncated file behind
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path))
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
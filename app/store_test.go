@@ -0,0 +1,149 @@
+package app
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type storedValue struct {
+	Name  string
+	Count int
+}
+
+func TestMemoryStore(t *testing.T) {
+	s := NewMemoryStore()
+
+	var got storedValue
+	if ok, err := s.Get("missing", &got); ok || err != nil {
+		t.Errorf("Get of a missing key = %v, %v", ok, err)
+	}
+	if err := s.Set("kept", storedValue{"a", 1}, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Set("expires", storedValue{"b", 2}, 20*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := s.Get("expires", &got); !ok || err != nil || got != (storedValue{"b", 2}) {
+		t.Errorf("Get before the TTL = %v, %v, %v", ok, err, got)
+	}
+
+	time.Sleep(40 * time.Millisecond)
+	if ok, _ := s.Get("expires", &got); ok {
+		t.Error("value found after its TTL")
+	}
+	if ok, err := s.Get("kept", &got); !ok || err != nil || got != (storedValue{"a", 1}) {
+		t.Errorf("value without a TTL = %v, %v, %v", ok, err, got)
+	}
+
+	if err := s.Delete("kept"); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := s.Get("kept", &got); ok {
+		t.Error("value found after Delete")
+	}
+}
+
+func TestState(t *testing.T) {
+	s := NewMemoryStore()
+	room := (&CommandContext{RoomID: "r1", store: s}).RoomState()
+	person := (&CommandContext{PersonID: "p1", store: s}).PersonState()
+
+	if err := room.Set("topic", "deploys", 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := person.Set("topic", "lunch", 0); err != nil {
+		t.Fatal(err)
+	}
+	var topic string
+	if ok, _ := room.Get("topic", &topic); !ok || topic != "deploys" {
+		t.Errorf("room topic = %q", topic)
+	}
+	if ok, _ := person.Get("topic", &topic); !ok || topic != "lunch" {
+		t.Errorf("person topic = %q", topic)
+	}
+	if ok, _ := s.Get("room/r1/topic", &topic); !ok || topic != "deploys" {
+		t.Errorf("room state is not stored under room/r1/")
+	}
+}
+
+func TestFileStore_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+	s, err := NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Set("kept", storedValue{"a", 1}, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Set("expires", storedValue{"b", 2}, 20*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Set("deleted", storedValue{"c", 3}, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete("deleted"); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(40 * time.Millisecond)
+
+	reloaded, err := NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got storedValue
+	if ok, err := reloaded.Get("kept", &got); !ok || err != nil || got != (storedValue{"a", 1}) {
+		t.Errorf("reloaded value = %v, %v, %v", ok, err, got)
+	}
+	if ok, _ := reloaded.Get("expires", &got); ok {
+		t.Error("expired value found after reload")
+	}
+	if ok, _ := reloaded.Get("deleted", &got); ok {
+		t.Error("deleted value found after reload")
+	}
+
+	if err := ioutil.WriteFile(path, []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileStore(path); err == nil {
+		t.Error("NewFileStore loaded a corrupt file")
+	}
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "state.json")
+
+	for _, data := range []string{`{"v":1}`, `{"v":2}`} {
+		if err := writeFileAtomic(path, []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+		got, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != data {
+			t.Errorf("file holds %s, want %s", got, data)
+		}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0600 {
+		t.Errorf("file mode = %v, want 0600", mode)
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Errorf("%d files left in the directory, want only %s", len(files), filepath.Base(path))
+	}
+
+	if err := writeFileAtomic(filepath.Join(dir, "missing", "state.json"), []byte("{}")); err == nil {
+		t.Error("writeFileAtomic succeeded in a missing directory")
+	}
+}
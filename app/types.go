@@ -1,7 +1,10 @@
 package app
 
 import (
+	"sync"
+
 	"../localtunnelme"
+	"../spark"
 	"github.com/Sirupsen/logrus"
 	"github.com/kataras/iris"
 	"github.com/robjporter/go-utils/filesystem/config"
@@ -12,4 +15,23 @@ type Application struct {
 	Log    *logrus.Logger
 	Server *iris.Application
 	Tunnel *localtunnelme.Tunnel
+	Spark  *ciscospark.Client
+
+	Commands *CommandRouter
+	Intents  *IntentRouter
+	Cards    *CardRouter
+	Events   *EventRouter
+	// Store holds conversation state, see spark.store.file
+	Store Store
+
+	// Identity of the bot, resolved by Run before events are processed
+	bot   *ciscospark.Person
+	botMu *sync.Mutex
+
+	// Webhook events already processed
+	seen *seenSet
+	// Webhook events waiting to be processed
+	queue *eventQueue
+	// Spark integration, when the token comes from OAuth
+	oauth *oauthIntegration
 }
@@ -0,0 +1,120 @@
+package app
+
+import (
+	stdContext "context"
+	"errors"
+	"strings"
+
+	"../spark"
+)
+
+// desiredWebhooks returns the webhooks the bot needs, one per resource:event
+// pair in spark.webhooks, all pointing at target
+func (a Application) desiredWebhooks(target string) []*ciscospark.WebhookRequest {
+	prefix := a.conf.GetString("spark.hookname")
+	roomID := a.conf.GetString("spark.roomid")
+	var hooks []*ciscospark.WebhookRequest
+	for _, spec := range strings.Split(a.conf.GetString("spark.webhooks"), ",") {
+		parts := strings.SplitN(strings.TrimSpace(spec), ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			continue
+		}
+		hook := &ciscospark.WebhookRequest{
+			Name:      prefix + "-" + parts[0] + "-" + parts[1],
+			TargetURL: target,
+			Resource:  parts[0],
+			Event:     parts[1],
+			Secret:    a.conf.GetString("spark.secret"),
+		}
+		if roomID != "" && (hook.Resource == "messages" || hook.Resource == "memberships") {
+			hook.Filter = "roomId=" + roomID
+		}
+		hooks = append(hooks, hook)
+	}
+	return hooks
+}
+
+// reconcileWebhooks creates the missing webhooks, updates the ones whose target
+// changed and removes stale ones named after spark.hookname, i.e. "<hookname>-..."
+func (a Application) reconcileWebhooks(ctx stdContext.Context, target string) error {
+	sparkClient := a.Spark
+	prefix := a.conf.GetString("spark.hookname")
+	if prefix == "" {
+		return errors.New("spark.hookname must be set to manage webhooks")
+	}
+
+	existing, _, err := sparkClient.Webhooks.GetAll(ctx, nil, nil)
+	if err != nil {
+		return err
+	}
+	current := make(map[string]*ciscospark.Webhook)
+	for _, webhook := range existing {
+		if strings.HasPrefix(webhook.Name, prefix+"-") {
+			current[webhook.Name] = webhook
+		}
+	}
+
+	for _, want := range a.desiredWebhooks(target) {
+		have, ok := current[want.Name]
+		delete(current, want.Name)
+		if ok && (have.Resource != want.Resource || have.Event != want.Event || have.Filter != want.Filter) {
+			// Only the name, target and secret of a webhook can be updated
+			a.Log.Info("WEBHOOK: replacing ", have.Name)
+			if _, err := sparkClient.Webhooks.DeleteWebhook(ctx, have.ID); err != nil {
+				return err
+			}
+			ok = false
+		}
+		if !ok {
+			webhook, _, err := sparkClient.Webhooks.Post(ctx, want)
+			if err != nil {
+				return err
+			}
+			a.Log.Info("WEBHOOK: created ", webhook.Name, " -> ", webhook.TargetURL)
+			continue
+		}
+		if have.TargetURL != want.TargetURL || have.Secret != want.Secret {
+			_, _, err := sparkClient.Webhooks.UpdateWebhook(ctx, have.ID, &ciscospark.UpdateWebhookRequest{
+				Name:      want.Name,
+				TargetURL: want.TargetURL,
+				Secret:    want.Secret,
+			})
+			if err != nil {
+				return err
+			}
+			a.Log.Info("WEBHOOK: updated ", have.Name, " -> ", want.TargetURL)
+		}
+	}
+
+	for _, stale := range current {
+		if _, err := sparkClient.Webhooks.DeleteWebhook(ctx, stale.ID); err != nil {
+			return err
+		}
+		a.Log.Info("WEBHOOK: removed stale ", stale.Name)
+	}
+	return nil
+}
+
+// removeWebhooks deletes every webhook named "<hookname>-..."
+func (a Application) removeWebhooks(ctx stdContext.Context) error {
+	sparkClient := a.Spark
+	prefix := a.conf.GetString("spark.hookname")
+	if prefix == "" {
+		return errors.New("spark.hookname must be set to manage webhooks")
+	}
+
+	existing, _, err := sparkClient.Webhooks.GetAll(ctx, nil, nil)
+	if err != nil {
+		return err
+	}
+	for _, webhook := range existing {
+		if !strings.HasPrefix(webhook.Name, prefix+"-") {
+			continue
+		}
+		if _, err := sparkClient.Webhooks.DeleteWebhook(ctx, webhook.ID); err != nil {
+			return err
+		}
+		a.Log.Info("WEBHOOK: removed ", webhook.Name)
+	}
+	return nil
+}
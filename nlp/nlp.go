@@ -30,7 +30,54 @@ func New() *NL { return &NL{Output: bytes.NewBufferString("")} }
 // P proccesses the expr and returns one of
 // the types passed as the i parameter to the RegistryModel
 // func filled with the data inside expr
-func (nl *NL) P(expr string) interface{} { return nl.models[nl.naive.Predict(expr)].fit(expr) }
+func (nl *NL) P(expr string) interface{} { return nl.Process(expr).Value }
+
+// Result is the outcome of processing an expression
+type Result struct {
+	// Value is the model filled with the data inside the expression,
+	// as returned by P
+	Value interface{}
+	// Filled contains the names of the fields found in the expression,
+	// in the order they appear
+	Filled []string
+}
+
+// IsFilled reports whether the field name was found in the expression
+func (r *Result) IsFilled(name string) bool {
+	for _, f := range r.Filled {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Process proccesses the expr like P and also reports which fields of
+// the model were filled, telling apart missing fields from zero values
+func (nl *NL) Process(expr string) *Result {
+	return nl.models[nl.naive.Predict(expr)].fit(expr)
+}
+
+// SetField parses value into the field name of v, a pointer to a registered
+// model, the way Process fills it. Times use the format and location of the model.
+func (nl *NL) SetField(v interface{}, name, value string) error {
+	val := reflect.ValueOf(v)
+	if val.Kind() != reflect.Ptr || val.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("can't set a field of %T, want a pointer to a model", v)
+	}
+	for _, m := range nl.models {
+		if m.tpy != val.Elem().Type() {
+			continue
+		}
+		for _, f := range m.fields {
+			if f.name == name {
+				return m.setField(val.Elem().Field(f.index), f, value)
+			}
+		}
+		return fmt.Errorf("model %s has no field %s", m.tpy, name)
+	}
+	return fmt.Errorf("model %s is not registered", val.Elem().Type())
+}
 
 // Learn maps the models samples to the models themselves and
 // returns an error if something occurred while learning
@@ -295,39 +342,62 @@ func selectBestMapping(scores []int) int {
 	return bestMapping
 }
 
-func (m *model) fit(expr string) interface{} {
+func (m *model) fit(expr string) *Result {
 	val := reflect.New(m.tpy)
+	res := &Result{Value: val.Interface()}
 	if len(expr) == 0 {
-		return val.Interface()
+		return res
 	}
 	exps := m.selectBestSample([]byte(expr))
-	if len(exps) > 0 {
-		for _, e := range exps {
-			switch t := e.field.kind.(type) {
-			case reflect.Kind:
-				switch t {
-				case reflect.String:
-					val.Elem().Field(e.field.index).SetString(string(e.value))
-				case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-					v, _ := strconv.ParseUint(string(e.value), 10, 0)
-					val.Elem().Field(e.field.index).SetUint(v)
-				case reflect.Float32, reflect.Float64:
-					v, _ := strconv.ParseFloat(string(e.value), 64)
-					val.Elem().Field(e.field.index).SetFloat(v)
-				case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-					v, _ := strconv.ParseInt(string(e.value), 10, 0)
-					val.Elem().Field(e.field.index).SetInt(v)
-				}
-			case time.Time:
-				v, _ := time.ParseInLocation(m.timeFormat, string(e.value), m.timeLocation)
-				val.Elem().Field(e.field.index).Set(reflect.ValueOf(v))
-			case time.Duration:
-				v, _ := time.ParseDuration(string(e.value))
-				val.Elem().Field(e.field.index).Set(reflect.ValueOf(v))
+	for _, e := range exps {
+		err := m.setField(val.Elem().Field(e.field.index), e.field, string(e.value))
+		if err == nil && len(e.value) > 0 && !res.IsFilled(e.field.name) {
+			res.Filled = append(res.Filled, e.field.name)
+		}
+	}
+	return res
+}
+
+// setField parses value into f, the field fl of the model
+func (m *model) setField(f reflect.Value, fl field, value string) error {
+	switch t := fl.kind.(type) {
+	case reflect.Kind:
+		switch t {
+		case reflect.String:
+			f.SetString(value)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			v, err := strconv.ParseUint(value, 10, 0)
+			if err != nil {
+				return err
 			}
+			f.SetUint(v)
+		case reflect.Float32, reflect.Float64:
+			v, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return err
+			}
+			f.SetFloat(v)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			v, err := strconv.ParseInt(value, 10, 0)
+			if err != nil {
+				return err
+			}
+			f.SetInt(v)
+		}
+	case time.Time:
+		v, err := time.ParseInLocation(m.timeFormat, value, m.timeLocation)
+		if err != nil {
+			return err
 		}
+		f.Set(reflect.ValueOf(v))
+	case time.Duration:
+		v, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		f.Set(reflect.ValueOf(v))
 	}
-	return val.Interface()
+	return nil
 }
 
 // isLimit returns true if s is a limit on expected[id]
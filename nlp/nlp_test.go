@@ -120,6 +120,84 @@ func TestNL_P(t *testing.T) {
 	}
 }
 
+func TestNL_Process(t *testing.T) {
+	type T struct {
+		Service string
+		Target  string
+		Count   int
+	}
+
+	nl := New()
+
+	err := nl.RegisterModel(T{}, []string{
+		"deploy {Service} to {Target}",
+		"scale {Service} to {Count}",
+	})
+	failTest(t, err)
+
+	err = nl.Learn()
+	failTest(t, err)
+
+	cases := []struct {
+		name       string
+		expression string
+		want       *T
+		filled     []string
+	}{
+		0: {
+			"all fields",
+			"deploy billing to staging",
+			&T{Service: "billing", Target: "staging"},
+			[]string{"Service", "Target"},
+		},
+		1: {
+			"missing field",
+			"deploy billing to",
+			&T{Service: "billing"},
+			[]string{"Service"},
+		},
+		2: {
+			"zero value",
+			"scale billing to 0",
+			&T{Service: "billing"},
+			[]string{"Service", "Count"},
+		},
+		3: {
+			"invalid value",
+			"scale billing to many",
+			&T{Service: "billing"},
+			[]string{"Service"},
+		},
+		4: {
+			"empty",
+			"",
+			&T{},
+			nil,
+		},
+	}
+	for i, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			res := nl.Process(tt.expression)
+			if !reflect.DeepEqual(res.Value, tt.want) {
+				t.Errorf("test#%d: got %v want %v", i, res.Value, tt.want)
+			}
+			if !reflect.DeepEqual(res.Filled, tt.filled) {
+				t.Errorf("test#%d: filled %v want %v", i, res.Filled, tt.filled)
+			}
+		})
+	}
+}
+
+func TestResult_IsFilled(t *testing.T) {
+	res := &Result{Filled: []string{"Service"}}
+	if !res.IsFilled("Service") {
+		t.Error("Service should be filled")
+	}
+	if res.IsFilled("Target") {
+		t.Error("Target should not be filled")
+	}
+}
+
 func TestNL_RegisterModel(t *testing.T) {
 	type fields struct {
 		models []*model
@@ -320,3 +398,54 @@ func TestWithTimeLocation(t *testing.T) {
 		})
 	}
 }
+
+func TestNL_SetField(t *testing.T) {
+	type T struct {
+		Service string
+		Count   int
+		At      time.Time
+		In      time.Duration
+	}
+	type U struct {
+		Name string
+	}
+
+	nl := New()
+	err := nl.RegisterModel(T{}, []string{"deploy {Service} at {At}"}, WithTimeFormat("2006-01-02T15:04"), WithTimeLocation(time.UTC))
+	failTest(t, err)
+
+	cases := []struct {
+		name    string
+		field   string
+		value   string
+		want    T
+		wantErr bool
+	}{
+		0: {"string", "Service", "billing", T{Service: "billing"}, false},
+		1: {"int", "Count", "3", T{Count: 3}, false},
+		2: {"invalid int", "Count", "three", T{}, true},
+		3: {"time in the model format", "At", "2017-09-25T10:13", T{At: time.Date(2017, 9, 25, 10, 13, 0, 0, time.UTC)}, false},
+		4: {"time in the default format", "At", "09-25-2017_10:13am", T{}, true},
+		5: {"duration", "In", "2h", T{In: 2 * time.Hour}, false},
+		6: {"unknown field", "Missing", "x", T{}, true},
+	}
+	for i, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var got T
+			err := nl.SetField(&got, tt.field, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("test#%d: error = %v, wantErr %v", i, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("test#%d: got %v want %v", i, got, tt.want)
+			}
+		})
+	}
+
+	if err := nl.SetField(&U{}, "Name", "x"); err == nil {
+		t.Error("SetField on a model that is not registered should fail")
+	}
+	if err := nl.SetField(T{}, "Service", "x"); err == nil {
+		t.Error("SetField on a model that is not a pointer should fail")
+	}
+}
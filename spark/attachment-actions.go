@@ -0,0 +1,142 @@
+package ciscospark
+
+import "context"
+
+const attachmentActionsBasePath = "v1/attachment/actions"
+
+// AdaptiveCardContentType is the content type of Adaptive Card attachments
+const AdaptiveCardContentType = "application/vnd.microsoft.card.adaptive"
+
+// AttachmentActionsService handles communication with the Attachment Actions
+// related methods of the Cisco Spark API.
+type AttachmentActionsService service
+
+// Attachment is a card sent with a message. Content is either one of the
+// card structs below or a json.RawMessage holding the card as is.
+type Attachment struct {
+	ContentType string      `json:"contentType,omitempty"`
+	Content     interface{} `json:"content,omitempty"`
+}
+
+// AdaptiveCard is the root of an Adaptive Card. Body holds card elements
+// such as TextBlock, FactSet, InputText or InputChoiceSet.
+type AdaptiveCard struct {
+	Schema  string        `json:"$schema,omitempty"`
+	Type    string        `json:"type"`
+	Version string        `json:"version"`
+	Body    []interface{} `json:"body,omitempty"`
+	Actions []CardAction  `json:"actions,omitempty"`
+}
+
+// TextBlock is an Adaptive Card text element
+type TextBlock struct {
+	Type   string `json:"type"`
+	Text   string `json:"text"`
+	Size   string `json:"size,omitempty"`
+	Weight string `json:"weight,omitempty"`
+	Wrap   bool   `json:"wrap,omitempty"`
+}
+
+// Fact is a title/value pair of a FactSet
+type Fact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+// FactSet is an Adaptive Card list of facts
+type FactSet struct {
+	Type  string `json:"type"`
+	Facts []Fact `json:"facts"`
+}
+
+// InputText is an Adaptive Card text input
+type InputText struct {
+	Type        string `json:"type"`
+	ID          string `json:"id"`
+	Placeholder string `json:"placeholder,omitempty"`
+	Value       string `json:"value,omitempty"`
+	IsMultiline bool   `json:"isMultiline,omitempty"`
+}
+
+// Choice is an option of an InputChoiceSet
+type Choice struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+// InputChoiceSet is an Adaptive Card choice input
+type InputChoiceSet struct {
+	Type          string   `json:"type"`
+	ID            string   `json:"id"`
+	Choices       []Choice `json:"choices"`
+	Value         string   `json:"value,omitempty"`
+	Style         string   `json:"style,omitempty"`
+	IsMultiSelect bool     `json:"isMultiSelect,omitempty"`
+}
+
+// CardAction is an Adaptive Card action. The Data of an Action.Submit is
+// merged into the inputs of the resulting AttachmentAction.
+type CardAction struct {
+	Type  string      `json:"type"`
+	Title string      `json:"title,omitempty"`
+	URL   string      `json:"url,omitempty"`
+	Data  interface{} `json:"data,omitempty"`
+}
+
+// AttachmentAction is a card submission
+type AttachmentAction struct {
+	ID        string                 `json:"id,omitempty"`
+	Type      string                 `json:"type,omitempty"`
+	MessageID string                 `json:"messageId,omitempty"`
+	Inputs    map[string]interface{} `json:"inputs,omitempty"`
+	PersonID  string                 `json:"personId,omitempty"`
+	RoomID    string                 `json:"roomId,omitempty"`
+	Created   Timestamp              `json:"created,omitempty"`
+}
+
+// NewAdaptiveCard returns a version 1.0 card with the given body elements
+func NewAdaptiveCard(body ...interface{}) *AdaptiveCard {
+	return &AdaptiveCard{
+		Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+		Type:    "AdaptiveCard",
+		Version: "1.0",
+		Body:    body,
+	}
+}
+
+// NewTextBlock returns a wrapping text element
+func NewTextBlock(text string) TextBlock {
+	return TextBlock{Type: "TextBlock", Text: text, Wrap: true}
+}
+
+// NewSubmitAction returns an Action.Submit button sending data with the card inputs
+func NewSubmitAction(title string, data interface{}) CardAction {
+	return CardAction{Type: "Action.Submit", Title: title, Data: data}
+}
+
+// NewCardAttachment wraps an Adaptive Card, or its raw JSON, in an Attachment
+func NewCardAttachment(card interface{}) Attachment {
+	return Attachment{ContentType: AdaptiveCardContentType, Content: card}
+}
+
+func (r AttachmentAction) String() string {
+	return Stringify(r)
+}
+
+// GetAttachmentAction ....
+func (s *AttachmentActionsService) GetAttachmentAction(ctx context.Context, attachmentActionID string) (*AttachmentAction, *Response, error) {
+	path := attachmentActionsBasePath + "/" + attachmentActionID
+
+	req, err := s.client.NewRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	attachmentAction := new(AttachmentAction)
+	resp, err := s.client.Do(ctx, req, attachmentAction)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return attachmentAction, resp, err
+}
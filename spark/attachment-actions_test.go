@@ -0,0 +1,74 @@
+package ciscospark_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	ciscospark "."
+	"./sparktest"
+)
+
+func TestAttachmentActionsService_GetAttachmentAction(t *testing.T) {
+	s := sparktest.NewServer(nil)
+	defer s.Close()
+	c := s.Client()
+	ctx := context.Background()
+	room := s.AddRoom(&ciscospark.Room{Title: "cards"})
+	alice := s.AddPerson(&ciscospark.Person{DisplayName: "Alice", Emails: []string{"alice@example.com"}})
+
+	card := ciscospark.NewAdaptiveCard(
+		ciscospark.NewTextBlock("Lunch?"),
+		ciscospark.InputChoiceSet{Type: "Input.ChoiceSet", ID: "place", Choices: []ciscospark.Choice{{Title: "Pizza", Value: "pizza"}}},
+	)
+	card.Actions = []ciscospark.CardAction{ciscospark.NewSubmitAction("Vote", map[string]string{"poll": "lunch"})}
+	message, _, err := c.Messages.Post(ctx, &ciscospark.MessageRequest{
+		RoomID:      room.ID,
+		Text:        "Lunch?",
+		Attachments: []ciscospark.Attachment{ciscospark.NewCardAttachment(card)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(message.Attachments) != 1 || message.Attachments[0].ContentType != ciscospark.AdaptiveCardContentType {
+		t.Fatalf("posted message has attachments %v", message.Attachments)
+	}
+	content, err := json.Marshal(message.Attachments[0].Content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var posted ciscospark.AdaptiveCard
+	if err := json.Unmarshal(content, &posted); err != nil {
+		t.Fatal(err)
+	}
+	if posted.Type != "AdaptiveCard" || len(posted.Body) != 2 || len(posted.Actions) != 1 || posted.Actions[0].Type != "Action.Submit" {
+		t.Errorf("posted card = %s", content)
+	}
+
+	action := s.AddAttachmentAction(&ciscospark.AttachmentAction{
+		MessageID: message.ID,
+		PersonID:  alice.ID,
+		RoomID:    room.ID,
+		Inputs:    map[string]interface{}{"place": "pizza", "poll": "lunch"},
+	})
+	got, resp, err := c.AttachmentActions.GetAttachmentAction(ctx, action.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Request.URL.Path != "/v1/attachment/actions/"+action.ID {
+		t.Errorf("path = %s", resp.Request.URL.Path)
+	}
+	if got.ID != action.ID || got.Type != "submit" || got.MessageID != message.ID || got.PersonID != alice.ID || got.RoomID != room.ID {
+		t.Errorf("attachment action = %v", got)
+	}
+	if got.Inputs["place"] != "pizza" || got.Inputs["poll"] != "lunch" {
+		t.Errorf("inputs = %v", got.Inputs)
+	}
+	if got.Created.IsZero() {
+		t.Error("attachment action has no created time")
+	}
+
+	if _, _, err := c.AttachmentActions.GetAttachmentAction(ctx, "unknown"); !ciscospark.IsNotFound(err) {
+		t.Errorf("GetAttachmentAction of an unknown action: error = %v, want not found", err)
+	}
+}
@@ -2,6 +2,7 @@ package ciscospark
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -17,6 +18,8 @@ const (
 	libraryVersion = "0.1.0"
 	userAgent      = "ciscospark/" + libraryVersion
 	mediaType      = "application/json"
+
+	trackingIDHeader = "TrackingID"
 )
 
 var (
@@ -35,9 +38,12 @@ type Client struct {
 	// User agent for client
 	UserAgent string
 
-	// Authorization is the authentication token
+	// Authorization is the authentication token, used unless a TokenSource is set
 	Authorization string
 
+	// tokenSource supplies the token of each request, see SetTokenSource
+	tokenSource TokenSource
+
 	common service // Reuse a single struct instead of allocating one for each service on the heap
 
 	// Services used for communicating with the APIC-EM API
@@ -51,9 +57,22 @@ type Client struct {
 	Organizations   *OrganizationsService
 	Licenses        *LicensesService
 	Roles           *RolesService
+	Contents        *ContentsService
+
+	AttachmentActions *AttachmentActionsService
+	Events            *EventsService
+
+	RoomTabs                 *RoomTabsService
+	ResourceGroupMemberships *ResourceGroupMembershipsService
 
 	// Optional function called after every successful request made to the Cisco Spark APIs
 	onRequestCompleted RequestCompletionCallback
+
+	// Optional policy for retrying rate limited and transiently failing requests
+	retryPolicy *RetryPolicy
+
+	// Largest file the Contents service downloads
+	maxContentSize int64
 }
 
 type service struct {
@@ -63,22 +82,22 @@ type service struct {
 // RequestCompletionCallback defines the type of the request callback function
 type RequestCompletionCallback func(*http.Request, *http.Response)
 
-// ListOptions specifies the optional parameters to various List methods that
-// support pagination.
-type ListOptions struct {
-	// For paginated result sets, page of results to retrieve.
-	Page int `url:"page,omitempty"`
-
-	// For paginated result sets, the number of results to include per page.
-	PerPage int `url:"per_page,omitempty"`
-}
-
 // Response is a Cisco Spark response. This wraps the standard http.Response returned from Cisco Spark.
 type Response struct {
 	*http.Response
 
 	// Monitoring URI
 	Monitor string
+
+	// NextPage is the URL of the next page of results, taken from the
+	// rel="next" entry of the Link header. Empty on the last page.
+	NextPage string
+
+	// Attempts is the number of times the request was sent, including retries.
+	Attempts int
+
+	// TrackingID identifies the request to Cisco support.
+	TrackingID string
 }
 
 // An ErrorResponse reports the error caused by an API request
@@ -140,6 +159,12 @@ func NewClient(httpClient *http.Client) *Client {
 	c.Organizations = (*OrganizationsService)(&c.common)
 	c.Licenses = (*LicensesService)(&c.common)
 	c.Roles = (*RolesService)(&c.common)
+	c.Contents = (*ContentsService)(&c.common)
+	c.AttachmentActions = (*AttachmentActionsService)(&c.common)
+	c.Events = (*EventsService)(&c.common)
+	c.RoomTabs = (*RoomTabsService)(&c.common)
+	c.ResourceGroupMemberships = (*ResourceGroupMembershipsService)(&c.common)
+	c.maxContentSize = DefaultMaxContentSize
 
 	return c
 }
@@ -172,6 +197,17 @@ func SetBaseURL(bu string) ClientOpt {
 	}
 }
 
+// SetHTTPClient is a client option for setting the HTTP client used to reach the API.
+func SetHTTPClient(httpClient *http.Client) ClientOpt {
+	return func(c *Client) error {
+		if httpClient == nil {
+			httpClient = http.DefaultClient
+		}
+		c.client = httpClient
+		return nil
+	}
+}
+
 // SetUserAgent is a client option for setting the user agent.
 func SetUserAgent(ua string) ClientOpt {
 	return func(c *Client) error {
@@ -182,8 +218,8 @@ func SetUserAgent(ua string) ClientOpt {
 
 // NewRequest creates an API request. A relative URL can be provided in urlStr, which will be resolved to the
 // BaseURL of the Client. Relative URLS should always be specified without a preceding slash. If specified, the
-// value pointed to by body is JSON encoded and included in as the request body.
-func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Request, error) {
+// value pointed to by body is JSON encoded and included in as the request body. The request is bound to ctx.
+func (c *Client) NewRequest(ctx context.Context, method, urlStr string, body interface{}) (*http.Request, error) {
 	rel, err := url.Parse(urlStr)
 	if err != nil {
 		return nil, err
@@ -203,6 +239,7 @@ func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Requ
 	if err != nil {
 		return nil, err
 	}
+	req = req.WithContext(ctx)
 
 	req.Header.Add("Content-Type", mediaType)
 	req.Header.Add("Accept", mediaType)
@@ -221,21 +258,23 @@ func (c *Client) OnRequestCompleted(rc RequestCompletionCallback) {
 // newResponse creates a new Response for the provided http.Response
 func newResponse(r *http.Response) *Response {
 	response := Response{Response: r}
+	response.NextPage = parseNextLink(r.Header)
+	response.TrackingID = r.Header.Get(trackingIDHeader)
 
 	return &response
 }
 
 // Do sends an API request and returns the API response. The API response is JSON decoded and stored in the value
 // pointed to by v, or returned as an error if an API error has occurred. If v implements the io.Writer interface,
-// the raw response will be written to v, without attempting to decode it.
-func (c *Client) Do(req *http.Request, v interface{}) (*Response, error) {
-	resp, err := c.client.Do(req)
+// the raw response will be written to v, without attempting to decode it. When a RetryPolicy is set, rate limited
+// and transiently failing requests are retried before the final response is handled. Cancelling ctx aborts the
+// request, including any wait between retries.
+func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*Response, error) {
+	req = req.WithContext(ctx)
+	resp, attempts, err := c.send(req)
 	if err != nil {
 		return nil, err
 	}
-	if c.onRequestCompleted != nil {
-		c.onRequestCompleted(req, resp)
-	}
 
 	defer func() {
 		if rerr := resp.Body.Close(); err == nil {
@@ -250,6 +289,7 @@ func (c *Client) Do(req *http.Request, v interface{}) (*Response, error) {
 	}()
 
 	response := newResponse(resp)
+	response.Attempts = attempts
 
 	err = CheckResponse(resp)
 	if err != nil {
@@ -257,6 +297,11 @@ func (c *Client) Do(req *http.Request, v interface{}) (*Response, error) {
 	}
 
 	if v != nil {
+		if c, ok := v.(responseChecker); ok {
+			if err := c.checkResponse(resp); err != nil {
+				return response, err
+			}
+		}
 		if w, ok := v.(io.Writer); ok {
 			_, err := io.Copy(w, resp.Body)
 			if err != nil {
@@ -273,9 +318,19 @@ func (c *Client) Do(req *http.Request, v interface{}) (*Response, error) {
 	return response, err
 }
 
+// responseChecker is implemented by the values passed to Do that need to
+// look at a successful response before its body is read into them.
+type responseChecker interface {
+	checkResponse(*http.Response) error
+}
+
 func (r *ErrorResponse) Error() string {
-	return fmt.Sprintf("%v %v: %d %v - %v",
-		r.HTTPResponse.Request.Method, r.HTTPResponse.Request.URL, r.HTTPResponse.StatusCode, r.Message, r.TrackingID)
+	message := r.Message
+	if message == "" && len(r.Errors) > 0 {
+		message = r.Errors[0].Description
+	}
+	return fmt.Sprintf("%v %v: %d %v (tracking ID: %v)",
+		r.HTTPResponse.Request.Method, r.HTTPResponse.Request.URL, r.HTTPResponse.StatusCode, message, r.TrackingID)
 }
 
 // CheckResponse checks the API response for errors, and returns them if present. A response is considered an
@@ -288,14 +343,11 @@ func CheckResponse(r *http.Response) error {
 
 	errorResponse := &ErrorResponse{HTTPResponse: r}
 	data, err := ioutil.ReadAll(r.Body)
-	if err == nil && data != nil {
+	if err == nil && len(data) > 0 {
 		json.Unmarshal(data, errorResponse)
 	}
-	if err == nil && len(data) > 0 {
-		err := json.Unmarshal(data, errorResponse)
-		if err != nil {
-			return err
-		}
+	if errorResponse.TrackingID == "" {
+		errorResponse.TrackingID = r.Header.Get(trackingIDHeader)
 	}
 
 	return errorResponse
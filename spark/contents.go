@@ -0,0 +1,150 @@
+package ciscospark
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const contentsBasePath = "v1/contents"
+
+// DefaultMaxContentSize is the largest file ContentsService.Download accepts
+// unless changed with SetMaxContentSize.
+const DefaultMaxContentSize = 100 << 20
+
+// ErrContentTooLarge is returned when a file exceeds the client's max content size.
+var ErrContentTooLarge = errors.New("ciscospark: content exceeds the max content size")
+
+// ErrForeignContentURL is returned for content URLs outside the host of the client's BaseURL.
+var ErrForeignContentURL = errors.New("ciscospark: content URL is not on the API host")
+
+// ContentsService handles downloading the files attached to messages.
+type ContentsService service
+
+// ContentInfo describes a file attached to a message
+type ContentInfo struct {
+	Name        string
+	ContentType string
+	Size        int64
+}
+
+func (r ContentInfo) String() string {
+	return Stringify(r)
+}
+
+// SetMaxContentSize is a client option for limiting the size of downloaded
+// files. A size of zero or less removes the limit.
+func SetMaxContentSize(size int64) ClientOpt {
+	return func(c *Client) error {
+		c.maxContentSize = size
+		return nil
+	}
+}
+
+// contentPath accepts either a content URL, as found in Message.Files, or a content ID. URLs must point at
+// the scheme and host of the client's BaseURL, so the token is never sent elsewhere.
+func (c *Client) contentPath(content string) (string, error) {
+	if !strings.Contains(content, "://") {
+		return contentsBasePath + "/" + content, nil
+	}
+	u, err := url.Parse(content)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != c.BaseURL.Scheme || !strings.EqualFold(u.Host, c.BaseURL.Host) {
+		return "", ErrForeignContentURL
+	}
+	return u.String(), nil
+}
+
+func newContentInfo(h http.Header, size int64) *ContentInfo {
+	info := &ContentInfo{ContentType: h.Get("Content-Type"), Size: size}
+	if _, params, err := mime.ParseMediaType(h.Get("Content-Disposition")); err == nil {
+		info.Name = params["filename"]
+	}
+	return info
+}
+
+// limitedWriter fails once more than n bytes were written to it.
+type limitedWriter struct {
+	w io.Writer
+	n int64
+}
+
+// checkResponse rejects a response whose Content-Length is already over the limit.
+func (l *limitedWriter) checkResponse(resp *http.Response) error {
+	if resp.ContentLength > l.n {
+		return ErrContentTooLarge
+	}
+	return nil
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if int64(len(p)) > l.n {
+		return 0, ErrContentTooLarge
+	}
+	l.n -= int64(len(p))
+	return l.w.Write(p)
+}
+
+// Info fetches the name, type and size of a file without downloading it.
+func (s *ContentsService) Info(ctx context.Context, content string) (*ContentInfo, *Response, error) {
+	path, err := s.client.contentPath(content)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := s.client.NewRequest(ctx, "HEAD", path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := s.client.Do(ctx, req, nil)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return newContentInfo(resp.Header, resp.ContentLength), resp, err
+}
+
+// Download streams a file to w. It fails with ErrContentTooLarge when the file
+// is larger than the max content size: before writing anything to w when the
+// API sends its Content-Length, else after writing at most the max content size.
+func (s *ContentsService) Download(ctx context.Context, content string, w io.Writer) (*ContentInfo, *Response, error) {
+	path, err := s.client.contentPath(content)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := s.client.NewRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	max := s.client.maxContentSize
+	cw := &countingWriter{w: w}
+	var dst io.Writer = cw
+	if max > 0 {
+		dst = &limitedWriter{w: cw, n: max}
+	}
+	resp, err := s.client.Do(ctx, req, dst)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return newContentInfo(resp.Header, cw.n), resp, err
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,125 @@
+package ciscospark_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	ciscospark "."
+	"./sparktest"
+)
+
+func TestContentsService_DownloadOnlyFromAPIHost(t *testing.T) {
+	s := sparktest.NewServer(nil)
+	defer s.Close()
+	c := s.Client()
+	ctx := context.Background()
+	room := s.AddRoom(&ciscospark.Room{Title: "contents"})
+
+	message, _, err := c.Messages.Post(ctx, &ciscospark.MessageRequest{
+		RoomID: room.ID,
+		Upload: &ciscospark.FileUpload{Name: "report.csv", Reader: strings.NewReader("a,b\n")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := new(bytes.Buffer)
+	if _, _, err := c.Contents.Download(ctx, message.Files[0], buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "a,b\n" {
+		t.Errorf("downloaded %q", buf.String())
+	}
+
+	host := strings.TrimPrefix(s.URL, "http://")
+	for _, foreign := range []string{
+		"http://example.com/v1/contents/1",
+		"https://" + host + "/v1/contents/1",
+		"http://" + host + "@example.com/v1/contents/1",
+	} {
+		if _, _, err := c.Contents.Download(ctx, foreign, new(bytes.Buffer)); err != ciscospark.ErrForeignContentURL {
+			t.Errorf("Download(%s): error = %v, want %v", foreign, err, ciscospark.ErrForeignContentURL)
+		}
+		if _, _, err := c.Contents.Info(ctx, foreign); err != ciscospark.ErrForeignContentURL {
+			t.Errorf("Info(%s): error = %v, want %v", foreign, err, ciscospark.ErrForeignContentURL)
+		}
+	}
+}
+
+func TestContentsService_Info(t *testing.T) {
+	s := sparktest.NewServer(nil)
+	defer s.Close()
+	c := s.Client()
+	ctx := context.Background()
+	room := s.AddRoom(&ciscospark.Room{Title: "contents"})
+	message, _, err := c.Messages.Post(ctx, &ciscospark.MessageRequest{
+		RoomID: room.ID,
+		Upload: &ciscospark.FileUpload{Name: "report.csv", ContentType: "text/csv", Reader: strings.NewReader("a,b\n")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, resp, err := c.Contents.Info(ctx, message.Files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Request.Method != "HEAD" {
+		t.Errorf("Info sent a %s request, want HEAD", resp.Request.Method)
+	}
+	want := ciscospark.ContentInfo{Name: "report.csv", ContentType: "text/csv", Size: 4}
+	if *info != want {
+		t.Errorf("Info = %v, want %v", info, want)
+	}
+
+	if _, _, err := c.Contents.Info(ctx, "unknown"); !ciscospark.IsNotFound(err) {
+		t.Errorf("Info of an unknown content: error = %v, want not found", err)
+	}
+}
+
+func TestContentsService_DownloadTooLarge(t *testing.T) {
+	s := sparktest.NewServer(nil)
+	defer s.Close()
+	c := s.Client(ciscospark.SetMaxContentSize(3))
+	ctx := context.Background()
+	room := s.AddRoom(&ciscospark.Room{Title: "contents"})
+	message, _, err := c.Messages.Post(ctx, &ciscospark.MessageRequest{
+		RoomID: room.ID,
+		Upload: &ciscospark.FileUpload{Name: "report.csv", Reader: strings.NewReader("a,b\n")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+	if _, _, err := c.Contents.Download(ctx, message.Files[0], buf); err != ciscospark.ErrContentTooLarge {
+		t.Errorf("error = %v, want ErrContentTooLarge", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("%d bytes were written before the size was rejected", buf.Len())
+	}
+}
+
+func TestContentsService_DownloadTooLargeWithoutLength(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("a,b\n"))
+		w.(http.Flusher).Flush()
+		w.Write([]byte("c,d\n"))
+	}))
+	defer srv.Close()
+	c, err := ciscospark.New(nil, ciscospark.SetBaseURL(srv.URL+"/"), ciscospark.SetMaxContentSize(6))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+	if _, _, err := c.Contents.Download(context.Background(), "id", buf); err != ciscospark.ErrContentTooLarge {
+		t.Errorf("error = %v, want ErrContentTooLarge", err)
+	}
+	if buf.Len() > 6 {
+		t.Errorf("%d bytes were written, more than the max content size", buf.Len())
+	}
+}
@@ -0,0 +1,83 @@
+package ciscospark
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+// Sentinel errors matched by ErrorResponse with errors.Is, for example
+// errors.Is(err, ciscospark.ErrNotFound).
+var (
+	ErrBadRequest   = errors.New("ciscospark: bad request")
+	ErrUnauthorized = errors.New("ciscospark: unauthorized")
+	ErrForbidden    = errors.New("ciscospark: forbidden")
+	ErrNotFound     = errors.New("ciscospark: not found")
+	ErrConflict     = errors.New("ciscospark: conflict")
+	ErrRateLimited  = errors.New("ciscospark: rate limited")
+	ErrServer       = errors.New("ciscospark: server error")
+)
+
+// StatusCode returns the HTTP status code of the response that caused the error.
+func (r *ErrorResponse) StatusCode() int {
+	if r.HTTPResponse == nil {
+		return 0
+	}
+	return r.HTTPResponse.StatusCode
+}
+
+// Is reports whether the error belongs to the class of the sentinel target.
+func (r *ErrorResponse) Is(target error) bool {
+	code := r.StatusCode()
+	switch target {
+	case ErrBadRequest:
+		return code == http.StatusBadRequest
+	case ErrUnauthorized:
+		return code == http.StatusUnauthorized
+	case ErrForbidden:
+		return code == http.StatusForbidden
+	case ErrNotFound:
+		return code == http.StatusNotFound
+	case ErrConflict:
+		return code == http.StatusConflict
+	case ErrRateLimited:
+		return code == http.StatusTooManyRequests
+	case ErrServer:
+		return code >= 500
+	}
+	return false
+}
+
+// IsNotFound reports whether err is an API error for a missing resource.
+func IsNotFound(err error) bool { return errors.Is(err, ErrNotFound) }
+
+// IsUnauthorized reports whether err is an API error for a missing or invalid token.
+func IsUnauthorized(err error) bool { return errors.Is(err, ErrUnauthorized) }
+
+// IsForbidden reports whether err is an API error for an action the token may not perform.
+func IsForbidden(err error) bool { return errors.Is(err, ErrForbidden) }
+
+// IsRateLimited reports whether err is an API error for a rate limited request.
+func IsRateLimited(err error) bool { return errors.Is(err, ErrRateLimited) }
+
+// RetryAfter returns how long to wait before retrying a failed request, taken
+// from the Retry-After header of an API error. Spark sends it with 429 and some
+// 503 responses. It returns zero when the header is missing or err is not an
+// API error.
+func RetryAfter(err error) time.Duration {
+	var errorResponse *ErrorResponse
+	if !errors.As(err, &errorResponse) || errorResponse.HTTPResponse == nil {
+		return 0
+	}
+	d, _ := parseRetryAfter(errorResponse.HTTPResponse.Header)
+	return d
+}
+
+// TrackingID returns the tracking ID of an API error, or an empty string.
+func TrackingID(err error) string {
+	var errorResponse *ErrorResponse
+	if errors.As(err, &errorResponse) {
+		return errorResponse.TrackingID
+	}
+	return ""
+}
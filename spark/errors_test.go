@@ -0,0 +1,89 @@
+package ciscospark_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	ciscospark "."
+	"./sparktest"
+)
+
+func TestErrorClassification(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(*sparktest.Server)
+		status     int
+		sentinel   error
+		retryAfter time.Duration
+	}{
+		{"bad request", func(s *sparktest.Server) { s.Fail(1, 400, "Bad request.") }, 400, ciscospark.ErrBadRequest, 0},
+		{"unauthorized", func(s *sparktest.Server) { s.RevokeToken("sparktest") }, 401, ciscospark.ErrUnauthorized, 0},
+		{"forbidden", func(s *sparktest.Server) { s.Fail(1, 403, "Forbidden.") }, 403, ciscospark.ErrForbidden, 0},
+		{"not found", nil, 404, ciscospark.ErrNotFound, 0},
+		{"conflict", func(s *sparktest.Server) { s.Fail(1, 409, "Conflict.") }, 409, ciscospark.ErrConflict, 0},
+		{"rate limited", func(s *sparktest.Server) { s.RateLimit(1, 7*time.Second) }, 429, ciscospark.ErrRateLimited, 7 * time.Second},
+		{"server error", func(s *sparktest.Server) { s.Fail(1, 503, "Service unavailable.") }, 503, ciscospark.ErrServer, 0},
+	}
+	sentinels := []error{
+		ciscospark.ErrBadRequest, ciscospark.ErrUnauthorized, ciscospark.ErrForbidden, ciscospark.ErrNotFound,
+		ciscospark.ErrConflict, ciscospark.ErrRateLimited, ciscospark.ErrServer,
+	}
+
+	for _, tt := range tests {
+		s := sparktest.NewServer(nil)
+		if tt.setup != nil {
+			tt.setup(s)
+		}
+		_, _, err := s.Client().Rooms.GetRoom(context.Background(), "missing")
+		s.Close()
+
+		errorResponse, ok := err.(*ciscospark.ErrorResponse)
+		if !ok {
+			t.Errorf("%s: error = %v, want an ErrorResponse", tt.name, err)
+			continue
+		}
+		if errorResponse.StatusCode() != tt.status {
+			t.Errorf("%s: status = %d, want %d", tt.name, errorResponse.StatusCode(), tt.status)
+		}
+		for _, sentinel := range sentinels {
+			if got := errors.Is(err, sentinel); got != (sentinel == tt.sentinel) {
+				t.Errorf("%s: errors.Is(err, %v) = %v", tt.name, sentinel, got)
+			}
+		}
+		checks := map[string]bool{
+			"IsNotFound":     ciscospark.IsNotFound(err) == (tt.sentinel == ciscospark.ErrNotFound),
+			"IsUnauthorized": ciscospark.IsUnauthorized(err) == (tt.sentinel == ciscospark.ErrUnauthorized),
+			"IsForbidden":    ciscospark.IsForbidden(err) == (tt.sentinel == ciscospark.ErrForbidden),
+			"IsRateLimited":  ciscospark.IsRateLimited(err) == (tt.sentinel == ciscospark.ErrRateLimited),
+		}
+		for check, ok := range checks {
+			if !ok {
+				t.Errorf("%s: %s is wrong", tt.name, check)
+			}
+		}
+		if got := ciscospark.RetryAfter(err); got != tt.retryAfter {
+			t.Errorf("%s: RetryAfter = %v, want %v", tt.name, got, tt.retryAfter)
+		}
+		if got := ciscospark.TrackingID(err); !strings.HasPrefix(got, "sparktest_") {
+			t.Errorf("%s: TrackingID = %q", tt.name, got)
+		}
+		if wrapped := fmt.Errorf("get room: %w", err); !errors.Is(wrapped, tt.sentinel) || ciscospark.TrackingID(wrapped) == "" {
+			t.Errorf("%s: the class or tracking ID of a wrapped error is lost", tt.name)
+		}
+	}
+}
+
+func TestErrorClassification_OtherErrors(t *testing.T) {
+	for _, err := range []error{nil, errors.New("connection refused"), context.Canceled} {
+		if ciscospark.IsNotFound(err) || ciscospark.IsUnauthorized(err) || ciscospark.IsForbidden(err) || ciscospark.IsRateLimited(err) {
+			t.Errorf("%v is classified as an API error", err)
+		}
+		if ciscospark.RetryAfter(err) != 0 || ciscospark.TrackingID(err) != "" {
+			t.Errorf("%v has a Retry-After or tracking ID", err)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package ciscospark
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const eventsBasePath = "v1/events"
+
+// EventsService handles communication with the Events related methods of
+// the Cisco Spark API. Events are only visible to compliance officers.
+type EventsService service
+
+// EventQueryParams ...
+type EventQueryParams struct {
+	Resource string    `url:"resource,omitempty"`
+	Type     string    `url:"type,omitempty"`
+	ActorID  string    `url:"actorId,omitempty"`
+	From     time.Time `url:"from,omitempty"`
+	To       time.Time `url:"to,omitempty"`
+	Max      int       `url:"max,omitempty"`
+}
+
+// Event is an activity in the organization. Data holds a *Message or
+// *Membership depending on Resource.
+type Event struct {
+	ID       string      `json:"id,omitempty"`
+	Resource string      `json:"resource,omitempty"`
+	Type     string      `json:"type,omitempty"`
+	AppID    string      `json:"appId,omitempty"`
+	ActorID  string      `json:"actorId,omitempty"`
+	OrgID    string      `json:"orgId,omitempty"`
+	Created  Timestamp   `json:"created,omitempty"`
+	Data     interface{} `json:"data,omitempty"`
+}
+
+type eventsRoot struct {
+	Events []*Event `json:"items"`
+}
+
+func (r Event) String() string {
+	return Stringify(r)
+}
+
+// UnmarshalJSON decodes Data into the payload type of the event resource.
+func (r *Event) UnmarshalJSON(b []byte) error {
+	type event Event
+	var raw struct {
+		*event
+		Data json.RawMessage `json:"data,omitempty"`
+	}
+	raw.event = (*event)(r)
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	data, err := decodeResourceData(r.Resource, raw.Data)
+	if err != nil {
+		return fmt.Errorf("ciscospark: decoding %s event data: %v", r.Resource, err)
+	}
+	r.Data = data
+	return nil
+}
+
+// Message returns the payload of a messages event.
+func (r *Event) Message() (*Message, bool) {
+	m, ok := r.Data.(*Message)
+	return m, ok
+}
+
+// Membership returns the payload of a memberships event.
+func (r *Event) Membership() (*Membership, bool) {
+	m, ok := r.Data.(*Membership)
+	return m, ok
+}
+
+// Get ....
+func (s *EventsService) Get(ctx context.Context, queryParams *EventQueryParams) ([]*Event, *Response, error) {
+	path := eventsBasePath
+	path, err := addOptions(path, queryParams)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(eventsRoot)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root.Events, resp, err
+}
+
+// EventsIterator iterates over the pages of an events list.
+type EventsIterator = Iterator[Event]
+
+// List returns an iterator that follows the Link header through every page of events.
+func (s *EventsService) List(ctx context.Context, queryParams *EventQueryParams, opt *ListOptions) *EventsIterator {
+	return newIterator[Event](ctx, s.client, eventsBasePath, queryParams, opt)
+}
+
+// GetAll returns the events from every page, up to the limit set in opt.
+func (s *EventsService) GetAll(ctx context.Context, queryParams *EventQueryParams, opt *ListOptions) ([]*Event, *Response, error) {
+	return s.List(ctx, queryParams, opt).All()
+}
+
+// GetEvent ....
+func (s *EventsService) GetEvent(ctx context.Context, eventID string) (*Event, *Response, error) {
+	path := eventsBasePath + "/" + eventID
+
+	req, err := s.client.NewRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	event := new(Event)
+	resp, err := s.client.Do(ctx, req, event)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return event, resp, err
+}
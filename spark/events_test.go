@@ -0,0 +1,102 @@
+package ciscospark_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ciscospark "."
+	"./sparktest"
+)
+
+func TestEventsService_TimeRange(t *testing.T) {
+	s := sparktest.NewServer(nil)
+	defer s.Close()
+	c := s.Client()
+	ctx := context.Background()
+	room := s.AddRoom(&ciscospark.Room{Title: "audit"})
+	s.AddMessage(&ciscospark.Message{RoomID: room.ID, PersonEmail: "alice@example.com", Text: "hello"})
+
+	from := time.Date(2017, 9, 25, 10, 13, 14, 0, time.UTC)
+	_, resp, err := c.Events.Get(ctx, &ciscospark.EventQueryParams{Resource: ciscospark.ResourceMessages, From: from})
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := resp.Request.URL.Query()
+	if got := query.Get("from"); got != "2017-09-25T10:13:14Z" {
+		t.Errorf("from = %q, want 2017-09-25T10:13:14Z", got)
+	}
+	if _, ok := query["to"]; ok {
+		t.Errorf("zero To is sent as %q", query.Get("to"))
+	}
+	if got := query.Get("resource"); got != ciscospark.ResourceMessages {
+		t.Errorf("resource = %q", got)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     int
+	}{
+		{"around now", now.Add(-time.Minute), now.Add(time.Minute), 1},
+		{"before", now.Add(-2 * time.Minute), now.Add(-time.Minute), 0},
+		{"after", now.Add(time.Minute), time.Time{}, 0},
+	}
+	for _, tt := range tests {
+		events, _, err := c.Events.GetAll(ctx, &ciscospark.EventQueryParams{From: tt.from, To: tt.to}, nil)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if len(events) != tt.want {
+			t.Errorf("%s: got %d events, want %d", tt.name, len(events), tt.want)
+		}
+	}
+}
+
+func TestEventsService_Data(t *testing.T) {
+	s := sparktest.NewServer(nil)
+	defer s.Close()
+	c := s.Client()
+	ctx := context.Background()
+	room := s.AddRoom(&ciscospark.Room{Title: "audit"})
+	s.AddMessage(&ciscospark.Message{RoomID: room.ID, PersonEmail: "alice@example.com", Text: "hello"})
+	if _, _, err := c.Memberships.Post(ctx, &ciscospark.MembershipRequest{RoomID: room.ID, PersonEmail: "bob@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	events, _, err := c.Events.GetAll(ctx, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2", len(events))
+	}
+	for _, event := range events {
+		switch event.Resource {
+		case ciscospark.ResourceMessages:
+			m, ok := event.Message()
+			if !ok || m.Text != "hello" || m.RoomID != room.ID {
+				t.Errorf("messages event data = %#v", event.Data)
+			}
+			if _, ok := event.Membership(); ok {
+				t.Error("messages event has membership data")
+			}
+		case ciscospark.ResourceMemberships:
+			m, ok := event.Membership()
+			if !ok || m.PersonEmail != "bob@example.com" || m.RoomID != room.ID {
+				t.Errorf("memberships event data = %#v", event.Data)
+			}
+		default:
+			t.Errorf("unexpected %s event", event.Resource)
+		}
+	}
+
+	event, _, err := c.Events.GetEvent(ctx, events[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m, ok := event.Message(); !ok || m.Text != "hello" {
+		t.Errorf("GetEvent data = %#v, want the message", event.Data)
+	}
+}
@@ -1,5 +1,7 @@
 package ciscospark
 
+import "context"
+
 const licensesBasePath = "v1/licenses"
 
 // LicensesService is an interface for interfacing with the Licenses
@@ -29,20 +31,20 @@ func (r License) String() string {
 }
 
 // Get ....
-func (s *LicensesService) Get(queryParams *GetLicensesQueryParams) ([]*License, *Response, error) {
+func (s *LicensesService) Get(ctx context.Context, queryParams *GetLicensesQueryParams) ([]*License, *Response, error) {
 	path := licensesBasePath
 	path, err := addOptions(path, queryParams)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", path, nil)
+	req, err := s.client.NewRequest(ctx, "GET", path, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	root := new(LicensesRoot)
-	resp, err := s.client.Do(req, root)
+	resp, err := s.client.Do(ctx, req, root)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -50,17 +52,30 @@ func (s *LicensesService) Get(queryParams *GetLicensesQueryParams) ([]*License,
 	return root.Licenses, resp, err
 }
 
+// LicensesIterator iterates over the pages of a licenses list.
+type LicensesIterator = Iterator[License]
+
+// List returns an iterator that follows the Link header through every page of licenses.
+func (s *LicensesService) List(ctx context.Context, queryParams *GetLicensesQueryParams, opt *ListOptions) *LicensesIterator {
+	return newIterator[License](ctx, s.client, licensesBasePath, queryParams, opt)
+}
+
+// GetAll returns the licenses from every page, up to the limit set in opt.
+func (s *LicensesService) GetAll(ctx context.Context, queryParams *GetLicensesQueryParams, opt *ListOptions) ([]*License, *Response, error) {
+	return s.List(ctx, queryParams, opt).All()
+}
+
 // GetLicense ....
-func (s *LicensesService) GetLicense(LicenseID string) (*License, *Response, error) {
+func (s *LicensesService) GetLicense(ctx context.Context, LicenseID string) (*License, *Response, error) {
 	path := licensesBasePath + "/" + LicenseID
 
-	req, err := s.client.NewRequest("GET", path, nil)
+	req, err := s.client.NewRequest(ctx, "GET", path, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	License := new(License)
-	resp, err := s.client.Do(req, License)
+	resp, err := s.client.Do(ctx, req, License)
 	if err != nil {
 		return nil, resp, err
 	}
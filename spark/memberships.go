@@ -1,5 +1,7 @@
 package ciscospark
 
+import "context"
+
 const membershipsBasePath = "v1/memberships"
 
 // MembershipsService handles communication with the Memberships related methods of
@@ -29,13 +31,14 @@ type UpdateMembershipRequest struct {
 
 // Membership ...
 type Membership struct {
-	ID          string `json:"id,omitempty"`
-	RoomID      string `json:"roomId,omitempty"`
-	PersonID    string `json:"personId,omitempty"`
-	PersonEmail string `json:"personEmail,omitempty"`
-	Created     string `json:"created,omitempty"`
-	IsModerator bool   `json:"isModerator,omitempty"`
-	IsMonitor   bool   `json:"isMonitor,omitempty"`
+	ID          string    `json:"id,omitempty"`
+	RoomID      string    `json:"roomId,omitempty"`
+	RoomType    string    `json:"roomType,omitempty"`
+	PersonID    string    `json:"personId,omitempty"`
+	PersonEmail string    `json:"personEmail,omitempty"`
+	Created     Timestamp `json:"created,omitempty"`
+	IsModerator bool      `json:"isModerator,omitempty"`
+	IsMonitor   bool      `json:"isMonitor,omitempty"`
 }
 
 type membershipsRoot struct {
@@ -51,20 +54,20 @@ func (r MembershipRequest) String() string {
 }
 
 // Get ....
-func (s *MembershipsService) Get(queryParams *MembershipQueryParams) ([]*Membership, *Response, error) {
+func (s *MembershipsService) Get(ctx context.Context, queryParams *MembershipQueryParams) ([]*Membership, *Response, error) {
 	path := membershipsBasePath
 	path, err := addOptions(path, queryParams)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", path, nil)
+	req, err := s.client.NewRequest(ctx, "GET", path, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	root := new(membershipsRoot)
-	resp, err := s.client.Do(req, root)
+	resp, err := s.client.Do(ctx, req, root)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -73,17 +76,30 @@ func (s *MembershipsService) Get(queryParams *MembershipQueryParams) ([]*Members
 
 }
 
+// MembershipsIterator iterates over the pages of a memberships list.
+type MembershipsIterator = Iterator[Membership]
+
+// List returns an iterator that follows the Link header through every page of memberships.
+func (s *MembershipsService) List(ctx context.Context, queryParams *MembershipQueryParams, opt *ListOptions) *MembershipsIterator {
+	return newIterator[Membership](ctx, s.client, membershipsBasePath, queryParams, opt)
+}
+
+// GetAll returns the memberships from every page, up to the limit set in opt.
+func (s *MembershipsService) GetAll(ctx context.Context, queryParams *MembershipQueryParams, opt *ListOptions) ([]*Membership, *Response, error) {
+	return s.List(ctx, queryParams, opt).All()
+}
+
 // Post ....
-func (s *MembershipsService) Post(membershipRequest *MembershipRequest) (*Membership, *Response, error) {
+func (s *MembershipsService) Post(ctx context.Context, membershipRequest *MembershipRequest) (*Membership, *Response, error) {
 	path := membershipsBasePath
 
-	req, err := s.client.NewRequest("POST", path, membershipRequest)
+	req, err := s.client.NewRequest(ctx, "POST", path, membershipRequest)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	response := new(Membership)
-	resp, err := s.client.Do(req, response)
+	resp, err := s.client.Do(ctx, req, response)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -92,16 +108,16 @@ func (s *MembershipsService) Post(membershipRequest *MembershipRequest) (*Member
 }
 
 // GetMembership ....
-func (s *MembershipsService) GetMembership(membershipID string) (*Membership, *Response, error) {
+func (s *MembershipsService) GetMembership(ctx context.Context, membershipID string) (*Membership, *Response, error) {
 	path := membershipsBasePath + "/" + membershipID
 
-	req, err := s.client.NewRequest("GET", path, nil)
+	req, err := s.client.NewRequest(ctx, "GET", path, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	membership := new(Membership)
-	resp, err := s.client.Do(req, membership)
+	resp, err := s.client.Do(ctx, req, membership)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -110,16 +126,16 @@ func (s *MembershipsService) GetMembership(membershipID string) (*Membership, *R
 }
 
 // UpdateMembership ....
-func (s *MembershipsService) UpdateMembership(membershipID string, updateMembershipRequest *UpdateMembershipRequest) (*Membership, *Response, error) {
+func (s *MembershipsService) UpdateMembership(ctx context.Context, membershipID string, updateMembershipRequest *UpdateMembershipRequest) (*Membership, *Response, error) {
 	path := membershipsBasePath + "/" + membershipID
 
-	req, err := s.client.NewRequest("PUT", path, updateMembershipRequest)
+	req, err := s.client.NewRequest(ctx, "PUT", path, updateMembershipRequest)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	membership := new(Membership)
-	resp, err := s.client.Do(req, membership)
+	resp, err := s.client.Do(ctx, req, membership)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -128,15 +144,15 @@ func (s *MembershipsService) UpdateMembership(membershipID string, updateMembers
 }
 
 // DeleteMembership ....
-func (s *MembershipsService) DeleteMembership(membershipID string) (*Response, error) {
+func (s *MembershipsService) DeleteMembership(ctx context.Context, membershipID string) (*Response, error) {
 	path := membershipsBasePath + "/" + membershipID
 
-	req, err := s.client.NewRequest("DELETE", path, nil)
+	req, err := s.client.NewRequest(ctx, "DELETE", path, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := s.client.Do(req, nil)
+	resp, err := s.client.Do(ctx, req, nil)
 	if err != nil {
 		return resp, err
 	}
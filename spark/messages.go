@@ -1,5 +1,10 @@
 package ciscospark
 
+import (
+	"context"
+	"net/http"
+)
+
 const messagesBasePath = "v1/messages"
 
 // MessagesService handles communication with the Messages related methods of
@@ -13,6 +18,14 @@ type MessageQueryParams struct {
 	BeforeMessage   string `url:"beforeMessage,omitempty"`
 	Max             int    `url:"max,omitempty"`
 	MentionedPeople string `url:"mentionedPeople,omitempty"`
+	ParentID        string `url:"parentId,omitempty"`
+}
+
+// DirectMessageQueryParams selects the 1:1 conversation with a person
+type DirectMessageQueryParams struct {
+	PersonID    string `url:"personId,omitempty"`
+	PersonEmail string `url:"personEmail,omitempty"`
+	ParentID    string `url:"parentId,omitempty"`
 }
 
 // MessageRequest represents the Spark messages
@@ -23,22 +36,40 @@ type MessageRequest struct {
 	ToPersonID    string   `json:"toPersonId,omitempty"`
 	ToPersonEmail string   `json:"toPersonEmail,omitempty"`
 	MarkDown      string   `json:"markdown,omitempty"`
+	ParentID      string   `json:"parentId,omitempty"`
+
+	// Attachments are Adaptive Cards; a message with a card also needs Text or MarkDown
+	Attachments []Attachment `json:"attachments,omitempty"`
+
+	// Upload is a local file to attach, sent as multipart/form-data. It can't be
+	// combined with Files or Attachments.
+	Upload *FileUpload `json:"-"`
+}
+
+// UpdateMessageRequest represents an edit of a Spark message
+type UpdateMessageRequest struct {
+	RoomID   string `json:"roomId,omitempty"`
+	Text     string `json:"text,omitempty"`
+	MarkDown string `json:"markdown,omitempty"`
 }
 
 // Message ...
 type Message struct {
-	ID              string   `json:"id,omitempty"`
-	RoomID          string   `json:"roomId,omitempty"`
-	ToPersonEmail   string   `json:"toPersonEmail,omitempty"`
-	ToPersonID      string   `json:"toPersonId,omitempty"`
-	Text            string   `json:"text,omitempty"`
-	PersonID        string   `json:"personId,omitempty"`
-	PersonEmail     string   `json:"personEmail,omitempty"`
-	Created         string   `json:"created,omitempty"`
-	MarkDown        string   `json:"markdown,omitempty"`
-	Files           []string `json:"files,omitempty"`
-	RoomType        string   `json:"roomType,omitempty"`
-	MentionedPeople []string `json:"mentionedPeople,omitempty"`
+	ID              string       `json:"id,omitempty"`
+	RoomID          string       `json:"roomId,omitempty"`
+	ToPersonEmail   string       `json:"toPersonEmail,omitempty"`
+	ToPersonID      string       `json:"toPersonId,omitempty"`
+	Text            string       `json:"text,omitempty"`
+	PersonID        string       `json:"personId,omitempty"`
+	PersonEmail     string       `json:"personEmail,omitempty"`
+	Created         Timestamp    `json:"created,omitempty"`
+	MarkDown        string       `json:"markdown,omitempty"`
+	Files           []string     `json:"files,omitempty"`
+	RoomType        string       `json:"roomType,omitempty"`
+	MentionedPeople []string     `json:"mentionedPeople,omitempty"`
+	ParentID        string       `json:"parentId,omitempty"`
+	Updated         Timestamp    `json:"updated,omitempty"`
+	Attachments     []Attachment `json:"attachments,omitempty"`
 }
 
 type messagesRoot struct {
@@ -54,20 +85,20 @@ func (r MessageRequest) String() string {
 }
 
 // Get ....
-func (s *MessagesService) Get(queryParams *MessageQueryParams) ([]*Message, *Response, error) {
+func (s *MessagesService) Get(ctx context.Context, queryParams *MessageQueryParams) ([]*Message, *Response, error) {
 	path := messagesBasePath
 	path, err := addOptions(path, queryParams)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", path, nil)
+	req, err := s.client.NewRequest(ctx, "GET", path, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	root := new(messagesRoot)
-	resp, err := s.client.Do(req, root)
+	resp, err := s.client.Do(ctx, req, root)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -76,17 +107,36 @@ func (s *MessagesService) Get(queryParams *MessageQueryParams) ([]*Message, *Res
 
 }
 
+// MessagesIterator iterates over the pages of a messages list.
+type MessagesIterator = Iterator[Message]
+
+// List returns an iterator that follows the Link header through every page of messages.
+func (s *MessagesService) List(ctx context.Context, queryParams *MessageQueryParams, opt *ListOptions) *MessagesIterator {
+	return newIterator[Message](ctx, s.client, messagesBasePath, queryParams, opt)
+}
+
+// GetAll returns the messages from every page, up to the limit set in opt.
+func (s *MessagesService) GetAll(ctx context.Context, queryParams *MessageQueryParams, opt *ListOptions) ([]*Message, *Response, error) {
+	return s.List(ctx, queryParams, opt).All()
+}
+
 // Post ....
-func (s *MessagesService) Post(messageRequest *MessageRequest) (*Message, *Response, error) {
+func (s *MessagesService) Post(ctx context.Context, messageRequest *MessageRequest) (*Message, *Response, error) {
 	path := messagesBasePath
 
-	req, err := s.client.NewRequest("POST", path, messageRequest)
+	var req *http.Request
+	var err error
+	if messageRequest != nil && messageRequest.Upload != nil {
+		req, err = s.client.NewMultipartRequest(ctx, "POST", path, messageRequest, messageRequest.Upload)
+	} else {
+		req, err = s.client.NewRequest(ctx, "POST", path, messageRequest)
+	}
 	if err != nil {
 		return nil, nil, err
 	}
 
 	response := new(Message)
-	resp, err := s.client.Do(req, response)
+	resp, err := s.client.Do(ctx, req, response)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -95,16 +145,56 @@ func (s *MessagesService) Post(messageRequest *MessageRequest) (*Message, *Respo
 }
 
 // GetMessage ....
-func (s *MessagesService) GetMessage(messageID string) (*Message, *Response, error) {
+func (s *MessagesService) GetMessage(ctx context.Context, messageID string) (*Message, *Response, error) {
+	path := messagesBasePath + "/" + messageID
+
+	req, err := s.client.NewRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	message := new(Message)
+	resp, err := s.client.Do(ctx, req, message)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return message, resp, err
+}
+
+// ListDirect returns the messages of the 1:1 conversation with a person
+func (s *MessagesService) ListDirect(ctx context.Context, queryParams *DirectMessageQueryParams) ([]*Message, *Response, error) {
+	path := messagesBasePath + "/direct"
+	path, err := addOptions(path, queryParams)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(messagesRoot)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root.Messages, resp, err
+}
+
+// UpdateMessage edits the text of a message
+func (s *MessagesService) UpdateMessage(ctx context.Context, messageID string, updateMessageRequest *UpdateMessageRequest) (*Message, *Response, error) {
 	path := messagesBasePath + "/" + messageID
 
-	req, err := s.client.NewRequest("GET", path, nil)
+	req, err := s.client.NewRequest(ctx, "PUT", path, updateMessageRequest)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	message := new(Message)
-	resp, err := s.client.Do(req, message)
+	resp, err := s.client.Do(ctx, req, message)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -113,15 +203,15 @@ func (s *MessagesService) GetMessage(messageID string) (*Message, *Response, err
 }
 
 // DeleteMessage ....
-func (s *MessagesService) DeleteMessage(messageID string) (*Response, error) {
+func (s *MessagesService) DeleteMessage(ctx context.Context, messageID string) (*Response, error) {
 	path := messagesBasePath + "/" + messageID
 
-	req, err := s.client.NewRequest("DELETE", path, nil)
+	req, err := s.client.NewRequest(ctx, "DELETE", path, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := s.client.Do(req, nil)
+	resp, err := s.client.Do(ctx, req, nil)
 	if err != nil {
 		return resp, err
 	}
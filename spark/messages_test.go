@@ -0,0 +1,119 @@
+package ciscospark_test
+
+import (
+	"context"
+	"testing"
+
+	ciscospark "."
+	"./sparktest"
+)
+
+func TestMessagesService_ListDirect(t *testing.T) {
+	s := sparktest.NewServer(nil)
+	defer s.Close()
+	c := s.Client()
+	ctx := context.Background()
+	alice := s.AddPerson(&ciscospark.Person{DisplayName: "Alice", Emails: []string{"alice@example.com"}})
+	s.AddPerson(&ciscospark.Person{DisplayName: "Bob", Emails: []string{"bob@example.com"}})
+
+	first, _, err := c.Messages.Post(ctx, &ciscospark.MessageRequest{ToPersonEmail: "alice@example.com", Text: "hello"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reply, _, err := c.Messages.Post(ctx, &ciscospark.MessageRequest{RoomID: first.RoomID, ParentID: first.ID, Text: "in a thread"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply.ParentID != first.ID {
+		t.Errorf("reply ParentID = %q, want %q", reply.ParentID, first.ID)
+	}
+	if _, _, err := c.Messages.Post(ctx, &ciscospark.MessageRequest{ToPersonEmail: "bob@example.com", Text: "not for alice"}); err != nil {
+		t.Fatal(err)
+	}
+
+	messages, resp, err := c.Messages.ListDirect(ctx, &ciscospark.DirectMessageQueryParams{PersonEmail: "alice@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := resp.Request.URL.Path; got != "/v1/messages/direct" {
+		t.Errorf("path = %s, want /v1/messages/direct", got)
+	}
+	if got := resp.Request.URL.RawQuery; got != "personEmail=alice%40example.com" {
+		t.Errorf("query = %s, want personEmail=alice%%40example.com", got)
+	}
+	if len(messages) != 2 || messages[0].ID != reply.ID || messages[1].ID != first.ID {
+		t.Errorf("ListDirect by email returned %d messages, want the reply and the first message", len(messages))
+	}
+
+	messages, resp, err = c.Messages.ListDirect(ctx, &ciscospark.DirectMessageQueryParams{PersonID: alice.ID, ParentID: first.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := resp.Request.URL.Query()
+	if query.Get("personId") != alice.ID || query.Get("parentId") != first.ID || query.Get("personEmail") != "" {
+		t.Errorf("query = %s", resp.Request.URL.RawQuery)
+	}
+	if len(messages) != 1 || messages[0].ID != reply.ID {
+		t.Errorf("ListDirect of the thread returned %d messages, want the reply", len(messages))
+	}
+}
+
+func TestMessagesService_ListThread(t *testing.T) {
+	s := sparktest.NewServer(nil)
+	defer s.Close()
+	c := s.Client()
+	ctx := context.Background()
+	room := s.AddRoom(&ciscospark.Room{Title: "threads"})
+
+	parent := s.AddMessage(&ciscospark.Message{RoomID: room.ID, PersonEmail: "alice@example.com", Text: "question"})
+	s.AddMessage(&ciscospark.Message{RoomID: room.ID, PersonEmail: "alice@example.com", Text: "unrelated"})
+	reply, _, err := c.Messages.Post(ctx, &ciscospark.MessageRequest{RoomID: room.ID, ParentID: parent.ID, Text: "answer"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages, _, err := c.Messages.GetAll(ctx, &ciscospark.MessageQueryParams{RoomID: room.ID, ParentID: parent.ID}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 1 || messages[0].ID != reply.ID || messages[0].ParentID != parent.ID {
+		t.Errorf("thread of %s returned %d messages, want the reply", parent.ID, len(messages))
+	}
+}
+
+func TestMessagesService_UpdateMessage(t *testing.T) {
+	s := sparktest.NewServer(nil)
+	defer s.Close()
+	c := s.Client()
+	ctx := context.Background()
+	room := s.AddRoom(&ciscospark.Room{Title: "edits"})
+
+	message, _, err := c.Messages.Post(ctx, &ciscospark.MessageRequest{RoomID: room.ID, Text: "helo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !message.Updated.IsZero() {
+		t.Errorf("new message has Updated = %v", message.Updated)
+	}
+
+	updated, resp, err := c.Messages.UpdateMessage(ctx, message.ID, &ciscospark.UpdateMessageRequest{RoomID: room.ID, Text: "hello"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Request.Method != "PUT" || resp.Request.URL.Path != "/v1/messages/"+message.ID {
+		t.Errorf("UpdateMessage sent %s %s", resp.Request.Method, resp.Request.URL.Path)
+	}
+	if updated.ID != message.ID || updated.Text != "hello" {
+		t.Errorf("updated message = %s %q", updated.ID, updated.Text)
+	}
+	if updated.Updated.IsZero() || updated.Created.IsZero() {
+		t.Errorf("updated message has Created = %v, Updated = %v", updated.Created, updated.Updated)
+	}
+	if stored := s.Messages(room.ID); len(stored) != 1 || stored[0].Text != "hello" {
+		t.Errorf("the fake API holds %d messages after the edit", len(stored))
+	}
+
+	if _, _, err := c.Messages.UpdateMessage(ctx, "unknown", &ciscospark.UpdateMessageRequest{RoomID: room.ID, Text: "hello"}); !ciscospark.IsNotFound(err) {
+		t.Errorf("UpdateMessage of an unknown message: error = %v, want not found", err)
+	}
+}
@@ -1,5 +1,7 @@
 package ciscospark
 
+import "context"
+
 const organizationsBasePath = "v1/organizations"
 
 // organizationsService is an interface for interfacing with the organizations
@@ -13,9 +15,9 @@ type GetOrganizationsQueryParams struct {
 
 // Organization represents the Spark organizations
 type Organization struct {
-	ID          string `json:"id,omitempty"`
-	DisplayName string `json:"displayName,omitempty"`
-	Created     string `json:"created,omitempty"`
+	ID          string    `json:"id,omitempty"`
+	DisplayName string    `json:"displayName,omitempty"`
+	Created     Timestamp `json:"created,omitempty"`
 }
 
 type organizationsRoot struct {
@@ -27,20 +29,20 @@ func (r Organization) String() string {
 }
 
 // Get ....
-func (s *OrganizationsService) Get(queryParams *GetOrganizationsQueryParams) ([]*Organization, *Response, error) {
+func (s *OrganizationsService) Get(ctx context.Context, queryParams *GetOrganizationsQueryParams) ([]*Organization, *Response, error) {
 	path := organizationsBasePath
 	path, err := addOptions(path, queryParams)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", path, nil)
+	req, err := s.client.NewRequest(ctx, "GET", path, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	root := new(organizationsRoot)
-	resp, err := s.client.Do(req, root)
+	resp, err := s.client.Do(ctx, req, root)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -48,17 +50,30 @@ func (s *OrganizationsService) Get(queryParams *GetOrganizationsQueryParams) ([]
 	return root.Organizations, resp, err
 }
 
+// OrganizationsIterator iterates over the pages of a organizations list.
+type OrganizationsIterator = Iterator[Organization]
+
+// List returns an iterator that follows the Link header through every page of organizations.
+func (s *OrganizationsService) List(ctx context.Context, queryParams *GetOrganizationsQueryParams, opt *ListOptions) *OrganizationsIterator {
+	return newIterator[Organization](ctx, s.client, organizationsBasePath, queryParams, opt)
+}
+
+// GetAll returns the organizations from every page, up to the limit set in opt.
+func (s *OrganizationsService) GetAll(ctx context.Context, queryParams *GetOrganizationsQueryParams, opt *ListOptions) ([]*Organization, *Response, error) {
+	return s.List(ctx, queryParams, opt).All()
+}
+
 // GetOrganization ....
-func (s *OrganizationsService) GetOrganization(OrganizationID string) (*Organization, *Response, error) {
+func (s *OrganizationsService) GetOrganization(ctx context.Context, OrganizationID string) (*Organization, *Response, error) {
 	path := organizationsBasePath + "/" + OrganizationID
 
-	req, err := s.client.NewRequest("GET", path, nil)
+	req, err := s.client.NewRequest(ctx, "GET", path, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	Organization := new(Organization)
-	resp, err := s.client.Do(req, Organization)
+	resp, err := s.client.Do(ctx, req, Organization)
 	if err != nil {
 		return nil, resp, err
 	}
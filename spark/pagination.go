@@ -0,0 +1,137 @@
+package ciscospark
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+)
+
+var linkNextRegexp = regexp.MustCompile(`<([^>]+)>\s*;\s*rel="?next"?`)
+
+// ErrNoMorePages is returned by Iterator.Next once the last page, or the
+// limit set in ListOptions, was reached.
+var ErrNoMorePages = errors.New("ciscospark: no more pages")
+
+// ListOptions specifies the optional parameters to the List and GetAll methods
+// that follow the Link header pagination of the Cisco Spark API.
+type ListOptions struct {
+	// Limit caps the total number of items returned across all pages.
+	// A zero value means every page is fetched.
+	Limit int
+}
+
+// parseNextLink returns the URL of the rel="next" entry of an RFC 5988 Link
+// header, or an empty string if there is none.
+func parseNextLink(h http.Header) string {
+	for _, link := range h["Link"] {
+		if m := linkNextRegexp.FindStringSubmatch(link); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// pager walks the pages of a list call by following rel="next" links.
+type pager struct {
+	ctx     context.Context
+	client  *Client
+	next    string
+	limit   int
+	count   int
+	started bool
+	err     error
+}
+
+func newPager(ctx context.Context, client *Client, path string, queryParams interface{}, opt *ListOptions) *pager {
+	p := &pager{ctx: ctx, client: client}
+	p.next, p.err = addOptions(path, queryParams)
+	if opt != nil {
+		p.limit = opt.Limit
+	}
+	return p
+}
+
+// HasNext reports whether another page can be fetched.
+func (p *pager) HasNext() bool {
+	if p.limit > 0 && p.count >= p.limit {
+		return false
+	}
+	return !p.started || p.next != ""
+}
+
+// fetch retrieves the next page and decodes it into root.
+func (p *pager) fetch(root interface{}) (*Response, error) {
+	if !p.HasNext() {
+		return nil, ErrNoMorePages
+	}
+	p.started = true
+	if p.err != nil {
+		p.next = ""
+		return nil, p.err
+	}
+
+	req, err := p.client.NewRequest(p.ctx, "GET", p.next, nil)
+	if err != nil {
+		p.next = ""
+		return nil, err
+	}
+
+	resp, err := p.client.Do(p.ctx, req, root)
+	if resp != nil {
+		p.next = resp.NextPage
+	} else {
+		p.next = ""
+	}
+	return resp, err
+}
+
+// take returns how many of the n items of the current page fit under the
+// limit and records them as consumed.
+func (p *pager) take(n int) int {
+	if p.limit > 0 && p.count+n > p.limit {
+		n = p.limit - p.count
+	}
+	p.count += n
+	return n
+}
+
+// Iterator iterates over the pages of a list of T, as returned by the List
+// method of the services.
+type Iterator[T any] struct {
+	*pager
+}
+
+func newIterator[T any](ctx context.Context, client *Client, path string, queryParams interface{}, opt *ListOptions) *Iterator[T] {
+	return &Iterator[T]{newPager(ctx, client, path, queryParams, opt)}
+}
+
+// Next returns the next page of items, or ErrNoMorePages after the last one.
+func (it *Iterator[T]) Next() ([]*T, *Response, error) {
+	root := new(struct {
+		Items []*T `json:"items"`
+	})
+	resp, err := it.fetch(root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root.Items[:it.take(len(root.Items))], resp, err
+}
+
+// All returns the items of every remaining page, up to the limit set in the
+// ListOptions of the iterator. The items read before an error are returned with it.
+func (it *Iterator[T]) All() ([]*T, *Response, error) {
+	var items []*T
+	var resp *Response
+	for it.HasNext() {
+		page, r, err := it.Next()
+		resp = r
+		if err != nil {
+			return items, resp, err
+		}
+		items = append(items, page...)
+	}
+
+	return items, resp, nil
+}
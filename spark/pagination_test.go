@@ -0,0 +1,66 @@
+package ciscospark_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	ciscospark "."
+	"./sparktest"
+)
+
+func TestIterator_NextAfterLastPage(t *testing.T) {
+	s := sparktest.NewServer(nil)
+	defer s.Close()
+	c := s.Client()
+	requests := 0
+	c.OnRequestCompleted(func(*http.Request, *http.Response) { requests++ })
+	room := s.AddRoom(&ciscospark.Room{Title: "paging"})
+	for _, text := range []string{"1", "2", "3"} {
+		s.AddMessage(&ciscospark.Message{RoomID: room.ID, Text: text})
+	}
+
+	it := c.Messages.List(context.Background(), &ciscospark.MessageQueryParams{RoomID: room.ID, Max: 2}, nil)
+	var texts []string
+	for it.HasNext() {
+		page, _, err := it.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, m := range page {
+			texts = append(texts, m.Text)
+		}
+	}
+	if len(texts) != 3 || requests != 2 {
+		t.Fatalf("read %v in %d requests, want 3 messages in 2", texts, requests)
+	}
+
+	page, resp, err := it.Next()
+	if err != ciscospark.ErrNoMorePages || page != nil || resp != nil {
+		t.Errorf("Next after the last page = %v, %v, %v, want ErrNoMorePages", page, resp, err)
+	}
+	if requests != 2 {
+		t.Errorf("Next after the last page sent a request")
+	}
+}
+
+func TestIterator_Limit(t *testing.T) {
+	s := sparktest.NewServer(nil)
+	defer s.Close()
+	c := s.Client()
+	for i := 0; i < 5; i++ {
+		s.AddRoom(&ciscospark.Room{Title: "room"})
+	}
+
+	it := c.Rooms.List(context.Background(), &ciscospark.RoomQueryParams{Max: 2}, &ciscospark.ListOptions{Limit: 3})
+	rooms, _, err := it.All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rooms) != 3 {
+		t.Errorf("All returned %d rooms, want the limit of 3", len(rooms))
+	}
+	if _, _, err := it.Next(); err != ciscospark.ErrNoMorePages {
+		t.Errorf("Next past the limit: error = %v, want ErrNoMorePages", err)
+	}
+}
@@ -1,7 +1,15 @@
 package ciscospark
 
+import (
+	"context"
+	"strings"
+)
+
 const peopleBasePath = "v1/people"
 
+// MaxPeopleIDs is the number of IDs a single people list call can look up
+const MaxPeopleIDs = 85
+
 // PeopleService is an interface for interfacing with the People
 // endpoints of the Cisco Spark API
 type PeopleService service
@@ -10,25 +18,41 @@ type PeopleService service
 type GetPeopleQueryParams struct {
 	Email       string `url:"email,omitempty"`
 	DisplayName string `url:"displayName,omitempty"`
+	ID          string `url:"id,omitempty"` // Comma separated list of up to MaxPeopleIDs IDs
+	OrgID       string `url:"orgId,omitempty"`
+	CallingData bool   `url:"callingData,omitempty"`
 	Max         int    `url:"max,omitempty"`
 }
 
-// Person represents the Spark people
-type Person struct {
-	ID          string   `json:"id,omitempty"`
+// PersonRequest represents the Spark people
+type PersonRequest struct {
 	Emails      []string `json:"emails,omitempty"`
 	DisplayName string   `json:"displayName,omitempty"`
 	FirstName   string   `json:"firstName,omitempty"`
 	LastName    string   `json:"lastName,omitempty"`
 	Avatar      string   `json:"avatar,omitempty"`
-	Created     string   `json:"created,omitempty"`
 	OrgID       string   `json:"orgId,omitempty"`
 	Roles       []string `json:"roles,omitempty"`
 	Licenses    []string `json:"licenses,omitempty"`
-	TimeZone    string   `json:"timezone,omitempty"`
-	Status      string   `json:"status,omitempty"`
-	NickName    string   `json:"nickName,omitempty"`
-	Type        string   `json:"type,omitempty"`
+}
+
+// Person represents the Spark people
+type Person struct {
+	ID           string    `json:"id,omitempty"`
+	Emails       []string  `json:"emails,omitempty"`
+	DisplayName  string    `json:"displayName,omitempty"`
+	FirstName    string    `json:"firstName,omitempty"`
+	LastName     string    `json:"lastName,omitempty"`
+	Avatar       string    `json:"avatar,omitempty"`
+	Created      Timestamp `json:"created,omitempty"`
+	OrgID        string    `json:"orgId,omitempty"`
+	Roles        []string  `json:"roles,omitempty"`
+	Licenses     []string  `json:"licenses,omitempty"`
+	TimeZone     string    `json:"timezone,omitempty"`
+	LastActivity Timestamp `json:"lastActivity,omitempty"`
+	Status       string    `json:"status,omitempty"`
+	NickName     string    `json:"nickName,omitempty"`
+	Type         string    `json:"type,omitempty"`
 }
 
 type peopleRoot struct {
@@ -39,21 +63,25 @@ func (r Person) String() string {
 	return Stringify(r)
 }
 
+func (r PersonRequest) String() string {
+	return Stringify(r)
+}
+
 // Get ....
-func (s *PeopleService) Get(queryParams *GetPeopleQueryParams) ([]*Person, *Response, error) {
+func (s *PeopleService) Get(ctx context.Context, queryParams *GetPeopleQueryParams) ([]*Person, *Response, error) {
 	path := peopleBasePath
 	path, err := addOptions(path, queryParams)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", path, nil)
+	req, err := s.client.NewRequest(ctx, "GET", path, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	root := new(peopleRoot)
-	resp, err := s.client.Do(req, root)
+	resp, err := s.client.Do(ctx, req, root)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -61,17 +89,70 @@ func (s *PeopleService) Get(queryParams *GetPeopleQueryParams) ([]*Person, *Resp
 	return root.People, resp, err
 }
 
+// PeopleIterator iterates over the pages of a people list.
+type PeopleIterator = Iterator[Person]
+
+// List returns an iterator that follows the Link header through every page of people.
+func (s *PeopleService) List(ctx context.Context, queryParams *GetPeopleQueryParams, opt *ListOptions) *PeopleIterator {
+	return newIterator[Person](ctx, s.client, peopleBasePath, queryParams, opt)
+}
+
+// GetAll returns the people from every page, up to the limit set in opt.
+func (s *PeopleService) GetAll(ctx context.Context, queryParams *GetPeopleQueryParams, opt *ListOptions) ([]*Person, *Response, error) {
+	return s.List(ctx, queryParams, opt).All()
+}
+
+// GetPeopleByID looks up people by ID, MaxPeopleIDs at a time. IDs that
+// match nobody are left out of the result.
+func (s *PeopleService) GetPeopleByID(ctx context.Context, personIDs []string) ([]*Person, *Response, error) {
+	var people []*Person
+	var resp *Response
+	for start := 0; start < len(personIDs); start += MaxPeopleIDs {
+		end := start + MaxPeopleIDs
+		if end > len(personIDs) {
+			end = len(personIDs)
+		}
+		queryParams := &GetPeopleQueryParams{ID: strings.Join(personIDs[start:end], ","), Max: end - start}
+		page, r, err := s.Get(ctx, queryParams)
+		resp = r
+		if err != nil {
+			return people, resp, err
+		}
+		people = append(people, page...)
+	}
+
+	return people, resp, nil
+}
+
+// Post ....
+func (s *PeopleService) Post(ctx context.Context, personRequest *PersonRequest) (*Person, *Response, error) {
+	path := peopleBasePath
+
+	req, err := s.client.NewRequest(ctx, "POST", path, personRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	response := new(Person)
+	resp, err := s.client.Do(ctx, req, response)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return response, resp, err
+}
+
 // GetPerson ....
-func (s *PeopleService) GetPerson(personID string) (*Person, *Response, error) {
+func (s *PeopleService) GetPerson(ctx context.Context, personID string) (*Person, *Response, error) {
 	path := peopleBasePath + "/" + personID
 
-	req, err := s.client.NewRequest("GET", path, nil)
+	req, err := s.client.NewRequest(ctx, "GET", path, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	person := new(Person)
-	resp, err := s.client.Do(req, person)
+	resp, err := s.client.Do(ctx, req, person)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -80,19 +161,55 @@ func (s *PeopleService) GetPerson(personID string) (*Person, *Response, error) {
 }
 
 // GetMe ....
-func (s *PeopleService) GetMe() (*Person, *Response, error) {
+func (s *PeopleService) GetMe(ctx context.Context) (*Person, *Response, error) {
 	path := peopleBasePath + "/me"
 
-	req, err := s.client.NewRequest("GET", path, nil)
+	req, err := s.client.NewRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	person := new(Person)
+	resp, err := s.client.Do(ctx, req, person)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return person, resp, err
+}
+
+// UpdatePerson replaces the details of a person. Fields left empty are
+// cleared, so send the complete person.
+func (s *PeopleService) UpdatePerson(ctx context.Context, personID string, personRequest *PersonRequest) (*Person, *Response, error) {
+	path := peopleBasePath + "/" + personID
+
+	req, err := s.client.NewRequest(ctx, "PUT", path, personRequest)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	person := new(Person)
-	resp, err := s.client.Do(req, person)
+	resp, err := s.client.Do(ctx, req, person)
 	if err != nil {
 		return nil, resp, err
 	}
 
 	return person, resp, err
 }
+
+// DeletePerson ....
+func (s *PeopleService) DeletePerson(ctx context.Context, personID string) (*Response, error) {
+	path := peopleBasePath + "/" + personID
+
+	req, err := s.client.NewRequest(ctx, "DELETE", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(ctx, req, nil)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, err
+}
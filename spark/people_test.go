@@ -0,0 +1,90 @@
+package ciscospark_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	ciscospark "."
+	"./sparktest"
+)
+
+func TestPeopleService_GetPeopleByID(t *testing.T) {
+	s := sparktest.NewServer(nil)
+	defer s.Close()
+	c := s.Client()
+	ctx := context.Background()
+
+	var ids []string
+	for i := 0; i < 2*ciscospark.MaxPeopleIDs+10; i++ {
+		p := s.AddPerson(&ciscospark.Person{DisplayName: fmt.Sprint("person ", i), Emails: []string{fmt.Sprintf("p%d@example.com", i)}})
+		ids = append(ids, p.ID)
+	}
+	ids = append(ids, "unknown")
+
+	var batches []int
+	c.OnRequestCompleted(func(req *http.Request, resp *http.Response) {
+		batches = append(batches, len(strings.Split(req.URL.Query().Get("id"), ",")))
+	})
+	people, _, err := c.People.GetPeopleByID(ctx, ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "[85 85 11]"; fmt.Sprint(batches) != want {
+		t.Errorf("IDs per request = %v, want %s", batches, want)
+	}
+	if len(people) != len(ids)-1 {
+		t.Fatalf("got %d people, want %d", len(people), len(ids)-1)
+	}
+	for i, p := range people {
+		if p.ID != ids[i] {
+			t.Errorf("person %d = %s, want %s", i, p.ID, ids[i])
+		}
+	}
+
+	batches = nil
+	if people, _, err := c.People.GetPeopleByID(ctx, nil); err != nil || len(people) != 0 || len(batches) != 0 {
+		t.Errorf("GetPeopleByID(nil) = %d people, %v after %d requests", len(people), err, len(batches))
+	}
+}
+
+func TestPeopleService_CreateUpdateDelete(t *testing.T) {
+	s := sparktest.NewServer(nil)
+	defer s.Close()
+	c := s.Client()
+	ctx := context.Background()
+
+	person, _, err := c.People.Post(ctx, &ciscospark.PersonRequest{
+		Emails:      []string{"carol@example.com"},
+		DisplayName: "Carol",
+		Roles:       []string{"role1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updated, resp, err := c.People.UpdatePerson(ctx, person.ID, &ciscospark.PersonRequest{
+		Emails:      person.Emails,
+		DisplayName: "Carol Smith",
+		Roles:       []string{"role1", "role2"},
+		Licenses:    []string{"license1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Request.Method != "PUT" {
+		t.Errorf("UpdatePerson sent a %s request, want PUT", resp.Request.Method)
+	}
+	if updated.DisplayName != "Carol Smith" || len(updated.Roles) != 2 || len(updated.Licenses) != 1 {
+		t.Errorf("updated person = %v", updated)
+	}
+
+	if _, err := c.People.DeletePerson(ctx, person.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := c.People.GetPerson(ctx, person.ID); !ciscospark.IsNotFound(err) {
+		t.Errorf("GetPerson after DeletePerson: error = %v, want not found", err)
+	}
+}
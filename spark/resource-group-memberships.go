@@ -0,0 +1,117 @@
+package ciscospark
+
+import "context"
+
+const resourceGroupMembershipsBasePath = "v1/resourceGroup/memberships"
+
+// ResourceGroupMembershipsService is an interface for interfacing with the ResourceGroupMemberships
+// endpoints of the Cisco Spark API
+type ResourceGroupMembershipsService service
+
+// ResourceGroupMembershipQueryParams ...
+type ResourceGroupMembershipQueryParams struct {
+	Max         int    `url:"max,omitempty"`
+	License     string `url:"license,omitempty"`
+	PersonID    string `url:"personId,omitempty"`
+	PersonOrgID string `url:"personOrgId,omitempty"`
+	Status      string `url:"status,omitempty"`
+}
+
+// UpdateResourceGroupMembershipRequest represents the Spark resourceGroupMemberships
+type UpdateResourceGroupMembershipRequest struct {
+	ResourceGroupID string `json:"resourceGroupId,omitempty"`
+	LicenseID       string `json:"licenseId,omitempty"`
+	PersonID        string `json:"personId,omitempty"`
+	PersonOrgID     string `json:"personOrgId,omitempty"`
+	Status          string `json:"status,omitempty"`
+}
+
+// ResourceGroupMembership assigns a person's hybrid service license to a resource group
+type ResourceGroupMembership struct {
+	ID              string `json:"id,omitempty"`
+	ResourceGroupID string `json:"resourceGroupId,omitempty"`
+	LicenseID       string `json:"licenseId,omitempty"`
+	PersonID        string `json:"personId,omitempty"`
+	PersonOrgID     string `json:"personOrgId,omitempty"`
+	Status          string `json:"status,omitempty"`
+}
+
+type resourceGroupMembershipsRoot struct {
+	ResourceGroupMemberships []*ResourceGroupMembership `json:"items"`
+}
+
+func (r ResourceGroupMembership) String() string {
+	return Stringify(r)
+}
+
+// Get ....
+func (s *ResourceGroupMembershipsService) Get(ctx context.Context, queryParams *ResourceGroupMembershipQueryParams) ([]*ResourceGroupMembership, *Response, error) {
+	path := resourceGroupMembershipsBasePath
+	path, err := addOptions(path, queryParams)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(resourceGroupMembershipsRoot)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root.ResourceGroupMemberships, resp, err
+
+}
+
+// ResourceGroupMembershipsIterator iterates over the pages of a resource group memberships list.
+type ResourceGroupMembershipsIterator = Iterator[ResourceGroupMembership]
+
+// List returns an iterator that follows the Link header through every page of resource group memberships.
+func (s *ResourceGroupMembershipsService) List(ctx context.Context, queryParams *ResourceGroupMembershipQueryParams, opt *ListOptions) *ResourceGroupMembershipsIterator {
+	return newIterator[ResourceGroupMembership](ctx, s.client, resourceGroupMembershipsBasePath, queryParams, opt)
+}
+
+// GetAll returns the resource group memberships from every page, up to the limit set in opt.
+func (s *ResourceGroupMembershipsService) GetAll(ctx context.Context, queryParams *ResourceGroupMembershipQueryParams, opt *ListOptions) ([]*ResourceGroupMembership, *Response, error) {
+	return s.List(ctx, queryParams, opt).All()
+}
+
+// GetResourceGroupMembership ....
+func (s *ResourceGroupMembershipsService) GetResourceGroupMembership(ctx context.Context, resourceGroupMembershipID string) (*ResourceGroupMembership, *Response, error) {
+	path := resourceGroupMembershipsBasePath + "/" + resourceGroupMembershipID
+
+	req, err := s.client.NewRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resourceGroupMembership := new(ResourceGroupMembership)
+	resp, err := s.client.Do(ctx, req, resourceGroupMembership)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return resourceGroupMembership, resp, err
+}
+
+// UpdateResourceGroupMembership ....
+func (s *ResourceGroupMembershipsService) UpdateResourceGroupMembership(ctx context.Context, resourceGroupMembershipID string, updateResourceGroupMembershipRequest *UpdateResourceGroupMembershipRequest) (*ResourceGroupMembership, *Response, error) {
+	path := resourceGroupMembershipsBasePath + "/" + resourceGroupMembershipID
+
+	req, err := s.client.NewRequest(ctx, "PUT", path, updateResourceGroupMembershipRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resourceGroupMembership := new(ResourceGroupMembership)
+	resp, err := s.client.Do(ctx, req, resourceGroupMembership)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return resourceGroupMembership, resp, err
+}
@@ -0,0 +1,69 @@
+package ciscospark_test
+
+import (
+	"context"
+	"testing"
+
+	ciscospark "."
+	"./sparktest"
+)
+
+func TestResourceGroupMembershipsService(t *testing.T) {
+	s := sparktest.NewServer(nil)
+	defer s.Close()
+	c := s.Client()
+	ctx := context.Background()
+	alice := s.AddPerson(&ciscospark.Person{DisplayName: "Alice", Emails: []string{"alice@example.com"}})
+	bob := s.AddPerson(&ciscospark.Person{DisplayName: "Bob", Emails: []string{"bob@example.com"}})
+	membership := s.AddResourceGroupMembership(&ciscospark.ResourceGroupMembership{
+		ResourceGroupID: "group1",
+		LicenseID:       "calendar",
+		PersonID:        alice.ID,
+		PersonOrgID:     "org1",
+	})
+	s.AddResourceGroupMembership(&ciscospark.ResourceGroupMembership{
+		ResourceGroupID: "group1",
+		LicenseID:       "calling",
+		PersonID:        bob.ID,
+		PersonOrgID:     "org1",
+		Status:          "activated",
+	})
+
+	memberships, resp, err := c.ResourceGroupMemberships.GetAll(ctx, &ciscospark.ResourceGroupMembershipQueryParams{License: "calendar", Status: "pending"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Request.URL.Path != "/v1/resourceGroup/memberships" {
+		t.Errorf("path = %s", resp.Request.URL.Path)
+	}
+	if len(memberships) != 1 || memberships[0].ID != membership.ID || memberships[0].PersonID != alice.ID {
+		t.Errorf("pending calendar memberships = %v", memberships)
+	}
+
+	updated, resp, err := c.ResourceGroupMemberships.UpdateResourceGroupMembership(ctx, membership.ID, &ciscospark.UpdateResourceGroupMembershipRequest{
+		ResourceGroupID: "group2",
+		LicenseID:       "calendar",
+		PersonID:        alice.ID,
+		PersonOrgID:     "org1",
+		Status:          "activated",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Request.Method != "PUT" {
+		t.Errorf("UpdateResourceGroupMembership sent a %s request, want PUT", resp.Request.Method)
+	}
+	got, _, err := c.ResourceGroupMemberships.GetResourceGroupMembership(ctx, membership.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range []*ciscospark.ResourceGroupMembership{updated, got} {
+		if m.ResourceGroupID != "group2" || m.Status != "activated" {
+			t.Errorf("updated membership = %v", m)
+		}
+	}
+
+	if _, _, err := c.ResourceGroupMemberships.GetResourceGroupMembership(ctx, "unknown"); !ciscospark.IsNotFound(err) {
+		t.Errorf("GetResourceGroupMembership of an unknown ID: error = %v, want not found", err)
+	}
+}
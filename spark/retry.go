@@ -0,0 +1,181 @@
+package ciscospark
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how Client.Do retries requests that were rate limited
+// (429) or hit a transient server error (502, 503, 504).
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt.
+	MaxRetries int
+
+	// MinBackoff is the base delay of the exponential backoff.
+	MinBackoff time.Duration
+
+	// MaxBackoff caps the delay between two attempts. A Retry-After header
+	// sent with a 429 is honoured even when it is longer.
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy is a reasonable policy for bots posting to busy rooms.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	MinBackoff: 500 * time.Millisecond,
+	MaxBackoff: 30 * time.Second,
+}
+
+// SetRetryPolicy is a client option for retrying rate limited and
+// transiently failing requests.
+func SetRetryPolicy(p RetryPolicy) ClientOpt {
+	return func(c *Client) error {
+		if p.MaxRetries < 0 {
+			return errors.New("ciscospark: MaxRetries can't be negative")
+		}
+		if p.MinBackoff <= 0 {
+			p.MinBackoff = DefaultRetryPolicy.MinBackoff
+		}
+		if p.MaxBackoff < p.MinBackoff {
+			p.MaxBackoff = p.MinBackoff
+		}
+		c.retryPolicy = &p
+		return nil
+	}
+}
+
+// shouldRetry reports whether a response with the given status code may be retried.
+func shouldRetry(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// backoff returns the delay before the given retry (starting at 1), using
+// exponential growth with jitter.
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	d := p.MinBackoff << uint(retry-1)
+	if d <= 0 || d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// parseRetryAfter reads a Retry-After header given either in seconds or as an HTTP date.
+func parseRetryAfter(h http.Header) (time.Duration, bool) {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := t.Sub(time.Now())
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+// send performs req, retrying according to the client's retry policy, and
+// once with a refreshed token when a refreshable token source was rejected.
+// It returns the last response and the number of attempts made.
+func (c *Client) send(req *http.Request) (*http.Response, int, error) {
+	attempts := 0
+	refreshed := false
+	var token *Token
+	for {
+		attempts++
+		if c.tokenSource != nil {
+			var err error
+			if token, err = c.authorize(req); err != nil {
+				return nil, attempts, err
+			}
+		}
+		resp, err := c.client.Do(req)
+		if err != nil {
+			return nil, attempts, err
+		}
+		if c.onRequestCompleted != nil {
+			c.onRequestCompleted(req, resp)
+		}
+
+		if resp.StatusCode == http.StatusUnauthorized && !refreshed && replayable(req) {
+			ts, ok := c.tokenSource.(RefreshableTokenSource)
+			if !ok {
+				return resp, attempts, nil
+			}
+			refreshed = true
+			if _, err := ts.Refresh(req.Context(), token); err != nil {
+				return resp, attempts, nil
+			}
+			io.CopyN(ioutil.Discard, resp.Body, 512)
+			resp.Body.Close()
+			if err := rewind(req); err != nil {
+				return nil, attempts, err
+			}
+			continue
+		}
+
+		p := c.retryPolicy
+		if p == nil || attempts > p.MaxRetries || !shouldRetry(resp.StatusCode) {
+			return resp, attempts, nil
+		}
+		if !replayable(req) {
+			return resp, attempts, nil
+		}
+
+		delay, ok := time.Duration(0), false
+		if resp.StatusCode == http.StatusTooManyRequests {
+			delay, ok = parseRetryAfter(resp.Header)
+		}
+		if !ok {
+			delay = p.backoff(attempts)
+		}
+
+		// Drain and close the body of the failed attempt so the connection can be reused
+		io.CopyN(ioutil.Discard, resp.Body, 512)
+		resp.Body.Close()
+
+		t := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			t.Stop()
+			return nil, attempts, req.Context().Err()
+		case <-t.C:
+		}
+
+		if err := rewind(req); err != nil {
+			return nil, attempts, err
+		}
+	}
+}
+
+// replayable reports whether req can be sent again.
+func replayable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// rewind resets the body of req before it is sent again.
+func rewind(req *http.Request) error {
+	if req.GetBody == nil {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return err
+	}
+	req.Body = body
+	return nil
+}
@@ -0,0 +1,90 @@
+package ciscospark_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	ciscospark "."
+	"./sparktest"
+)
+
+var testRetryPolicy = ciscospark.RetryPolicy{MaxRetries: 3, MinBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond}
+
+func TestClient_RetryReplaysBody(t *testing.T) {
+	s := sparktest.NewServer(nil)
+	defer s.Close()
+	c := s.Client(ciscospark.SetRetryPolicy(testRetryPolicy))
+	room := s.AddRoom(&ciscospark.Room{Title: "retry"})
+
+	s.RateLimit(2, 0)
+	_, resp, err := c.Messages.Post(context.Background(), &ciscospark.MessageRequest{RoomID: room.ID, Text: "deploy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Attempts != 3 {
+		t.Errorf("Attempts = %d, want 3", resp.Attempts)
+	}
+	messages := s.Messages(room.ID)
+	if len(messages) != 1 || messages[0].Text != "deploy" {
+		t.Errorf("stored messages = %v, want one with the posted text", messages)
+	}
+}
+
+func TestClient_RetryReplaysUpload(t *testing.T) {
+	s := sparktest.NewServer(nil)
+	defer s.Close()
+	c := s.Client(ciscospark.SetRetryPolicy(testRetryPolicy))
+	room := s.AddRoom(&ciscospark.Room{Title: "retry"})
+
+	s.RateLimit(1, 0)
+	message, resp, err := c.Messages.Post(context.Background(), &ciscospark.MessageRequest{
+		RoomID: room.ID,
+		Text:   "report",
+		Upload: &ciscospark.FileUpload{Name: "report.csv", Reader: strings.NewReader("a,b\n")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Attempts != 2 {
+		t.Errorf("Attempts = %d, want 2", resp.Attempts)
+	}
+	if message.Text != "report" || len(message.Files) != 1 {
+		t.Errorf("message = %v, want the text and one file", message)
+	}
+}
+
+func TestClient_RetryGivesUp(t *testing.T) {
+	s := sparktest.NewServer(nil)
+	defer s.Close()
+	c := s.Client(ciscospark.SetRetryPolicy(ciscospark.RetryPolicy{MaxRetries: 1, MinBackoff: time.Millisecond}))
+
+	s.RateLimit(5, 0)
+	_, resp, err := c.People.GetMe(context.Background())
+	if _, ok := err.(*ciscospark.ErrorResponse); !ok {
+		t.Fatalf("error = %v, want an ErrorResponse", err)
+	}
+	if resp.StatusCode != http.StatusTooManyRequests || resp.Attempts != 2 {
+		t.Errorf("status = %d after %d attempts, want 429 after 2", resp.StatusCode, resp.Attempts)
+	}
+}
+
+func TestClient_RetryHonoursContext(t *testing.T) {
+	s := sparktest.NewServer(nil)
+	defer s.Close()
+	c := s.Client(ciscospark.SetRetryPolicy(testRetryPolicy))
+
+	s.RateLimit(1, time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, _, err := c.People.GetMe(ctx)
+	if err != context.DeadlineExceeded {
+		t.Errorf("error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("waited %v for the Retry-After instead of the context", elapsed)
+	}
+}
@@ -1,5 +1,7 @@
 package ciscospark
 
+import "context"
+
 const rolesBasePath = "v1/roles"
 
 // rolesService is an interface for interfacing with the roles
@@ -26,20 +28,20 @@ func (r Role) String() string {
 }
 
 // Get ....
-func (s *RolesService) Get(queryParams *GetRolesQueryParams) ([]*Role, *Response, error) {
+func (s *RolesService) Get(ctx context.Context, queryParams *GetRolesQueryParams) ([]*Role, *Response, error) {
 	path := rolesBasePath
 	path, err := addOptions(path, queryParams)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", path, nil)
+	req, err := s.client.NewRequest(ctx, "GET", path, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	root := new(rolesRoot)
-	resp, err := s.client.Do(req, root)
+	resp, err := s.client.Do(ctx, req, root)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -47,17 +49,30 @@ func (s *RolesService) Get(queryParams *GetRolesQueryParams) ([]*Role, *Response
 	return root.Roles, resp, err
 }
 
+// RolesIterator iterates over the pages of a roles list.
+type RolesIterator = Iterator[Role]
+
+// List returns an iterator that follows the Link header through every page of roles.
+func (s *RolesService) List(ctx context.Context, queryParams *GetRolesQueryParams, opt *ListOptions) *RolesIterator {
+	return newIterator[Role](ctx, s.client, rolesBasePath, queryParams, opt)
+}
+
+// GetAll returns the roles from every page, up to the limit set in opt.
+func (s *RolesService) GetAll(ctx context.Context, queryParams *GetRolesQueryParams, opt *ListOptions) ([]*Role, *Response, error) {
+	return s.List(ctx, queryParams, opt).All()
+}
+
 // GetRole ....
-func (s *RolesService) GetRole(RoleID string) (*Role, *Response, error) {
+func (s *RolesService) GetRole(ctx context.Context, RoleID string) (*Role, *Response, error) {
 	path := rolesBasePath + "/" + RoleID
 
-	req, err := s.client.NewRequest("GET", path, nil)
+	req, err := s.client.NewRequest(ctx, "GET", path, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	Role := new(Role)
-	resp, err := s.client.Do(req, Role)
+	resp, err := s.client.Do(ctx, req, Role)
 	if err != nil {
 		return nil, resp, err
 	}
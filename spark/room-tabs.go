@@ -0,0 +1,159 @@
+package ciscospark
+
+import "context"
+
+const roomTabsBasePath = "v1/room/tabs"
+
+// RoomTabsService is an interface for interfacing with the RoomTabs
+// endpoints of the Cisco Spark API
+type RoomTabsService service
+
+// RoomTabQueryParams ...
+type RoomTabQueryParams struct {
+	Max    int    `url:"max,omitempty"`
+	RoomID string `url:"roomId,omitempty"`
+}
+
+// RoomTabRequest represents the Spark roomTabs
+type RoomTabRequest struct {
+	RoomID      string `json:"roomId,omitempty"`
+	ContentURL  string `json:"contentUrl,omitempty"`
+	DisplayName string `json:"displayName,omitempty"`
+}
+
+// UpdateRoomTabRequest represents the Spark roomTabs
+type UpdateRoomTabRequest struct {
+	RoomID      string `json:"roomId,omitempty"`
+	ContentURL  string `json:"contentUrl,omitempty"`
+	DisplayName string `json:"displayName,omitempty"`
+}
+
+// RoomTab is a URL pinned to the header of a room
+type RoomTab struct {
+	ID          string    `json:"id,omitempty"`
+	RoomID      string    `json:"roomId,omitempty"`
+	RoomType    string    `json:"roomType,omitempty"`
+	DisplayName string    `json:"displayName,omitempty"`
+	ContentURL  string    `json:"contentUrl,omitempty"`
+	CreatorID   string    `json:"creatorId,omitempty"`
+	Created     Timestamp `json:"created,omitempty"`
+}
+
+type roomTabsRoot struct {
+	RoomTabs []*RoomTab `json:"items"`
+}
+
+func (r RoomTab) String() string {
+	return Stringify(r)
+}
+
+func (r RoomTabRequest) String() string {
+	return Stringify(r)
+}
+
+// Get ....
+func (s *RoomTabsService) Get(ctx context.Context, queryParams *RoomTabQueryParams) ([]*RoomTab, *Response, error) {
+	path := roomTabsBasePath
+	path, err := addOptions(path, queryParams)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(roomTabsRoot)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root.RoomTabs, resp, err
+
+}
+
+// RoomTabsIterator iterates over the pages of a room tabs list.
+type RoomTabsIterator = Iterator[RoomTab]
+
+// List returns an iterator that follows the Link header through every page of room tabs.
+func (s *RoomTabsService) List(ctx context.Context, queryParams *RoomTabQueryParams, opt *ListOptions) *RoomTabsIterator {
+	return newIterator[RoomTab](ctx, s.client, roomTabsBasePath, queryParams, opt)
+}
+
+// GetAll returns the room tabs from every page, up to the limit set in opt.
+func (s *RoomTabsService) GetAll(ctx context.Context, queryParams *RoomTabQueryParams, opt *ListOptions) ([]*RoomTab, *Response, error) {
+	return s.List(ctx, queryParams, opt).All()
+}
+
+// Post ....
+func (s *RoomTabsService) Post(ctx context.Context, roomTabRequest *RoomTabRequest) (*RoomTab, *Response, error) {
+	path := roomTabsBasePath
+
+	req, err := s.client.NewRequest(ctx, "POST", path, roomTabRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	response := new(RoomTab)
+	resp, err := s.client.Do(ctx, req, response)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return response, resp, err
+}
+
+// GetRoomTab ....
+func (s *RoomTabsService) GetRoomTab(ctx context.Context, roomTabID string) (*RoomTab, *Response, error) {
+	path := roomTabsBasePath + "/" + roomTabID
+
+	req, err := s.client.NewRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	roomTab := new(RoomTab)
+	resp, err := s.client.Do(ctx, req, roomTab)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return roomTab, resp, err
+}
+
+// UpdateRoomTab ....
+func (s *RoomTabsService) UpdateRoomTab(ctx context.Context, roomTabID string, updateRoomTabRequest *UpdateRoomTabRequest) (*RoomTab, *Response, error) {
+	path := roomTabsBasePath + "/" + roomTabID
+
+	req, err := s.client.NewRequest(ctx, "PUT", path, updateRoomTabRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	roomTab := new(RoomTab)
+	resp, err := s.client.Do(ctx, req, roomTab)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return roomTab, resp, err
+}
+
+// DeleteRoomTab ....
+func (s *RoomTabsService) DeleteRoomTab(ctx context.Context, roomTabID string) (*Response, error) {
+	path := roomTabsBasePath + "/" + roomTabID
+
+	req, err := s.client.NewRequest(ctx, "DELETE", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(ctx, req, nil)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, err
+}
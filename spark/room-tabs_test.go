@@ -0,0 +1,72 @@
+package ciscospark_test
+
+import (
+	"context"
+	"testing"
+
+	ciscospark "."
+	"./sparktest"
+)
+
+func TestRoomTabsService(t *testing.T) {
+	s := sparktest.NewServer(nil)
+	defer s.Close()
+	c := s.Client()
+	ctx := context.Background()
+	incident := s.AddRoom(&ciscospark.Room{Title: "incident"})
+	other := s.AddRoom(&ciscospark.Room{Title: "other"})
+
+	tab, resp, err := c.RoomTabs.Post(ctx, &ciscospark.RoomTabRequest{
+		RoomID:      incident.ID,
+		ContentURL:  "https://dashboards.example.com/incident",
+		DisplayName: "Dashboard",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Request.URL.Path != "/v1/room/tabs" {
+		t.Errorf("path = %s", resp.Request.URL.Path)
+	}
+	if tab.ID == "" || tab.RoomID != incident.ID || tab.RoomType != "group" || tab.CreatorID != s.Me().ID || tab.Created.IsZero() {
+		t.Errorf("created tab = %v", tab)
+	}
+	if _, _, err := c.RoomTabs.Post(ctx, &ciscospark.RoomTabRequest{RoomID: other.ID, ContentURL: "https://example.com", DisplayName: "Other"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tabs, _, err := c.RoomTabs.GetAll(ctx, &ciscospark.RoomTabQueryParams{RoomID: incident.ID}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tabs) != 1 || tabs[0].ID != tab.ID {
+		t.Errorf("tabs of the incident room = %v", tabs)
+	}
+
+	updated, _, err := c.RoomTabs.UpdateRoomTab(ctx, tab.ID, &ciscospark.UpdateRoomTabRequest{
+		RoomID:      incident.ID,
+		ContentURL:  "https://dashboards.example.com/incident/42",
+		DisplayName: "Incident 42",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _, err := c.RoomTabs.GetRoomTab(ctx, tab.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tb := range []*ciscospark.RoomTab{updated, got} {
+		if tb.DisplayName != "Incident 42" || tb.ContentURL != "https://dashboards.example.com/incident/42" {
+			t.Errorf("updated tab = %v", tb)
+		}
+	}
+
+	if _, err := c.RoomTabs.DeleteRoomTab(ctx, tab.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := c.RoomTabs.GetRoomTab(ctx, tab.ID); !ciscospark.IsNotFound(err) {
+		t.Errorf("GetRoomTab after delete: error = %v, want not found", err)
+	}
+	if _, _, err := c.RoomTabs.Post(ctx, &ciscospark.RoomTabRequest{RoomID: "unknown", ContentURL: "https://example.com", DisplayName: "x"}); !ciscospark.IsNotFound(err) {
+		t.Errorf("Post to an unknown room: error = %v, want not found", err)
+	}
+}
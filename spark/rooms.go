@@ -1,7 +1,16 @@
 package ciscospark
 
+import "context"
+
 const roomsBasePath = "v1/rooms"
 
+// Room list orders for RoomQueryParams.SortBy
+const (
+	RoomSortByID           = "id"
+	RoomSortByLastActivity = "lastactivity"
+	RoomSortByCreated      = "created"
+)
+
 // RoomsService is an interface for interfacing with the Rooms
 // endpoints of the Cisco Spark API
 type RoomsService service
@@ -11,6 +20,7 @@ type RoomQueryParams struct {
 	Max    int    `url:"max,omitempty"`
 	TeamID string `url:"teamId,omitempty"`
 	Type   string `url:"type,omitempty"`
+	SortBy string `url:"sortBy,omitempty"`
 }
 
 // RoomRequest represents the Spark rooms
@@ -21,18 +31,31 @@ type RoomRequest struct {
 
 // UpdateRoomRequest represents the Spark rooms
 type UpdateRoomRequest struct {
-	Title string `json:"title,omitempty"`
+	Title    string `json:"title,omitempty"`
+	IsLocked *bool  `json:"isLocked,omitempty"`
+	TeamID   string `json:"teamId,omitempty"`
 }
 
 // Room ...
 type Room struct {
-	ID           string `json:"id,omitempty"`
-	Title        string `json:"title,omitempty"`
-	Type         string `json:"type,omitempty"`
-	IsLocked     bool   `json:"isLocked,omitempty"`
-	TeamID       string `json:"teamId,omitempty"`
-	LastActivity string `json:"lastActivity,omitempty"`
-	Created      string `json:"created,omitempty"`
+	ID           string    `json:"id,omitempty"`
+	Title        string    `json:"title,omitempty"`
+	Type         string    `json:"type,omitempty"`
+	IsLocked     bool      `json:"isLocked,omitempty"`
+	TeamID       string    `json:"teamId,omitempty"`
+	CreatorID    string    `json:"creatorId,omitempty"`
+	LastActivity Timestamp `json:"lastActivity,omitempty"`
+	Created      Timestamp `json:"created,omitempty"`
+}
+
+// MeetingInfo holds the details for joining the meeting of a room
+type MeetingInfo struct {
+	RoomID               string `json:"roomId,omitempty"`
+	MeetingLink          string `json:"meetingLink,omitempty"`
+	SipAddress           string `json:"sipAddress,omitempty"`
+	MeetingNumber        string `json:"meetingNumber,omitempty"`
+	CallInTollFreeNumber string `json:"callInTollFreeNumber,omitempty"`
+	CallInTollNumber     string `json:"callInTollNumber,omitempty"`
 }
 
 type roomsRoot struct {
@@ -47,21 +70,25 @@ func (r RoomRequest) String() string {
 	return Stringify(r)
 }
 
+func (r MeetingInfo) String() string {
+	return Stringify(r)
+}
+
 // Get ....
-func (s *RoomsService) Get(queryParams *RoomQueryParams) ([]*Room, *Response, error) {
+func (s *RoomsService) Get(ctx context.Context, queryParams *RoomQueryParams) ([]*Room, *Response, error) {
 	path := roomsBasePath
 	path, err := addOptions(path, queryParams)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", path, nil)
+	req, err := s.client.NewRequest(ctx, "GET", path, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	root := new(roomsRoot)
-	resp, err := s.client.Do(req, root)
+	resp, err := s.client.Do(ctx, req, root)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -70,17 +97,30 @@ func (s *RoomsService) Get(queryParams *RoomQueryParams) ([]*Room, *Response, er
 
 }
 
+// RoomsIterator iterates over the pages of a rooms list.
+type RoomsIterator = Iterator[Room]
+
+// List returns an iterator that follows the Link header through every page of rooms.
+func (s *RoomsService) List(ctx context.Context, queryParams *RoomQueryParams, opt *ListOptions) *RoomsIterator {
+	return newIterator[Room](ctx, s.client, roomsBasePath, queryParams, opt)
+}
+
+// GetAll returns the rooms from every page, up to the limit set in opt.
+func (s *RoomsService) GetAll(ctx context.Context, queryParams *RoomQueryParams, opt *ListOptions) ([]*Room, *Response, error) {
+	return s.List(ctx, queryParams, opt).All()
+}
+
 // Post ....
-func (s *RoomsService) Post(roomRequest *RoomRequest) (*Room, *Response, error) {
+func (s *RoomsService) Post(ctx context.Context, roomRequest *RoomRequest) (*Room, *Response, error) {
 	path := roomsBasePath
 
-	req, err := s.client.NewRequest("POST", path, roomRequest)
+	req, err := s.client.NewRequest(ctx, "POST", path, roomRequest)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	response := new(Room)
-	resp, err := s.client.Do(req, response)
+	resp, err := s.client.Do(ctx, req, response)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -89,16 +129,16 @@ func (s *RoomsService) Post(roomRequest *RoomRequest) (*Room, *Response, error)
 }
 
 // GetRoom ....
-func (s *RoomsService) GetRoom(roomID string) (*Room, *Response, error) {
+func (s *RoomsService) GetRoom(ctx context.Context, roomID string) (*Room, *Response, error) {
 	path := roomsBasePath + "/" + roomID
 
-	req, err := s.client.NewRequest("GET", path, nil)
+	req, err := s.client.NewRequest(ctx, "GET", path, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	room := new(Room)
-	resp, err := s.client.Do(req, room)
+	resp, err := s.client.Do(ctx, req, room)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -106,17 +146,35 @@ func (s *RoomsService) GetRoom(roomID string) (*Room, *Response, error) {
 	return room, resp, err
 }
 
+// GetMeetingInfo ....
+func (s *RoomsService) GetMeetingInfo(ctx context.Context, roomID string) (*MeetingInfo, *Response, error) {
+	path := roomsBasePath + "/" + roomID + "/meetingInfo"
+
+	req, err := s.client.NewRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	meetingInfo := new(MeetingInfo)
+	resp, err := s.client.Do(ctx, req, meetingInfo)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return meetingInfo, resp, err
+}
+
 // UpdateRoom ....
-func (s *RoomsService) UpdateRoom(roomID string, updateRoomRequest *UpdateRoomRequest) (*Room, *Response, error) {
+func (s *RoomsService) UpdateRoom(ctx context.Context, roomID string, updateRoomRequest *UpdateRoomRequest) (*Room, *Response, error) {
 	path := roomsBasePath + "/" + roomID
 
-	req, err := s.client.NewRequest("PUT", path, updateRoomRequest)
+	req, err := s.client.NewRequest(ctx, "PUT", path, updateRoomRequest)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	room := new(Room)
-	resp, err := s.client.Do(req, room)
+	resp, err := s.client.Do(ctx, req, room)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -125,15 +183,15 @@ func (s *RoomsService) UpdateRoom(roomID string, updateRoomRequest *UpdateRoomRe
 }
 
 // DeleteRoom ....
-func (s *RoomsService) DeleteRoom(roomID string) (*Response, error) {
+func (s *RoomsService) DeleteRoom(ctx context.Context, roomID string) (*Response, error) {
 	path := roomsBasePath + "/" + roomID
 
-	req, err := s.client.NewRequest("DELETE", path, nil)
+	req, err := s.client.NewRequest(ctx, "DELETE", path, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := s.client.Do(req, nil)
+	resp, err := s.client.Do(ctx, req, nil)
 	if err != nil {
 		return resp, err
 	}
@@ -0,0 +1,108 @@
+package ciscospark_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	ciscospark "."
+	"./sparktest"
+)
+
+func TestRoomsService_UpdateRoomLock(t *testing.T) {
+	s := sparktest.NewServer(nil)
+	defer s.Close()
+	c := s.Client()
+	ctx := context.Background()
+
+	room, _, err := c.Rooms.Post(ctx, &ciscospark.RoomRequest{Title: "moderated"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if room.CreatorID != s.Me().ID || room.Created.IsZero() {
+		t.Errorf("new room has CreatorID %q and Created %v", room.CreatorID, room.Created)
+	}
+
+	tests := []struct {
+		name     string
+		request  ciscospark.UpdateRoomRequest
+		title    string
+		isLocked bool
+	}{
+		{"lock", ciscospark.UpdateRoomRequest{Title: "moderated", IsLocked: ciscospark.Bool(true)}, "moderated", true},
+		{"rename keeps the lock", ciscospark.UpdateRoomRequest{Title: "renamed"}, "renamed", true},
+		{"unlock", ciscospark.UpdateRoomRequest{Title: "renamed", IsLocked: ciscospark.Bool(false)}, "renamed", false},
+	}
+	for _, tt := range tests {
+		updated, _, err := c.Rooms.UpdateRoom(ctx, room.ID, &tt.request)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		got, _, err := c.Rooms.GetRoom(ctx, room.ID)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		for _, r := range []*ciscospark.Room{updated, got} {
+			if r.Title != tt.title || r.IsLocked != tt.isLocked {
+				t.Errorf("%s: room = %q locked %v, want %q locked %v", tt.name, r.Title, r.IsLocked, tt.title, tt.isLocked)
+			}
+		}
+	}
+}
+
+func TestRoomsService_SortBy(t *testing.T) {
+	s := sparktest.NewServer(nil)
+	defer s.Close()
+	c := s.Client()
+	ctx := context.Background()
+	for _, title := range []string{"a", "b", "c"} {
+		s.AddRoom(&ciscospark.Room{Title: title})
+	}
+
+	for _, sortBy := range []string{ciscospark.RoomSortByID, ciscospark.RoomSortByLastActivity, ciscospark.RoomSortByCreated} {
+		rooms, resp, err := c.Rooms.Get(ctx, &ciscospark.RoomQueryParams{SortBy: sortBy})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := resp.Request.URL.Query().Get("sortBy"); got != sortBy {
+			t.Errorf("sortBy = %q, want %q", got, sortBy)
+		}
+		if len(rooms) != 3 {
+			t.Errorf("sortBy %s returned %d rooms", sortBy, len(rooms))
+		}
+		if sortBy == ciscospark.RoomSortByID && !sort.SliceIsSorted(rooms, func(i, j int) bool { return rooms[i].ID < rooms[j].ID }) {
+			t.Errorf("rooms sorted by id are out of order")
+		}
+	}
+
+	_, resp, err := c.Rooms.Get(ctx, &ciscospark.RoomQueryParams{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Request.URL.RawQuery != "" {
+		t.Errorf("query without sortBy = %q", resp.Request.URL.RawQuery)
+	}
+}
+
+func TestRoomsService_GetMeetingInfo(t *testing.T) {
+	s := sparktest.NewServer(nil)
+	defer s.Close()
+	c := s.Client()
+	ctx := context.Background()
+	room := s.AddRoom(&ciscospark.Room{Title: "standup"})
+
+	info, resp, err := c.Rooms.GetMeetingInfo(ctx, room.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Request.URL.Path != "/v1/rooms/"+room.ID+"/meetingInfo" {
+		t.Errorf("path = %s", resp.Request.URL.Path)
+	}
+	if info.RoomID != room.ID || info.MeetingLink == "" || info.SipAddress == "" {
+		t.Errorf("meeting info = %v", info)
+	}
+
+	if _, _, err := c.Rooms.GetMeetingInfo(ctx, "unknown"); !ciscospark.IsNotFound(err) {
+		t.Errorf("GetMeetingInfo of an unknown room: error = %v, want not found", err)
+	}
+}
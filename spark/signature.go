@@ -0,0 +1,63 @@
+package ciscospark
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"net/http"
+)
+
+// SignatureHeader is the header Cisco Spark uses to sign webhook payloads
+// when the webhook was registered with a secret.
+const SignatureHeader = "X-Spark-Signature"
+
+var (
+	// ErrMissingSignature is returned when a webhook request carries no signature.
+	ErrMissingSignature = errors.New("ciscospark: missing " + SignatureHeader + " header")
+
+	// ErrInvalidSignature is returned when a webhook signature does not match its payload.
+	ErrInvalidSignature = errors.New("ciscospark: invalid " + SignatureHeader + " header")
+)
+
+// Signature returns the hex encoded HMAC-SHA1 of body keyed with secret, as
+// sent by Cisco Spark in the X-Spark-Signature header.
+func Signature(secret string, body []byte) string {
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ValidSignature reports whether signature is the signature of body for secret.
+func ValidSignature(secret string, body []byte, signature string) bool {
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// VerifyWebhookRequest checks the X-Spark-Signature header of an incoming
+// webhook request against secret. The body is read and put back on r so it
+// can still be decoded by the caller, and is returned as well.
+func VerifyWebhookRequest(r *http.Request, secret string) ([]byte, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	r.Body.Close()
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	signature := r.Header.Get(SignatureHeader)
+	if signature == "" {
+		return body, ErrMissingSignature
+	}
+	if !ValidSignature(secret, body, signature) {
+		return body, ErrInvalidSignature
+	}
+	return body, nil
+}
@@ -0,0 +1,69 @@
+package ciscospark_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	ciscospark "."
+	"./sparktest"
+)
+
+func TestVerifyWebhookRequest(t *testing.T) {
+	s := sparktest.NewServer(nil)
+	defer s.Close()
+
+	type result struct {
+		body     []byte
+		reread   []byte
+		verified error
+	}
+	results := make(chan result, 1)
+	var secret string
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ciscospark.VerifyWebhookRequest(r, secret)
+		reread, _ := ioutil.ReadAll(r.Body)
+		results <- result{body, reread, err}
+	}))
+	defer target.Close()
+
+	tests := []struct {
+		name   string
+		signed string
+		secret string
+		want   error
+	}{
+		{"valid", "s3cret", "s3cret", nil},
+		{"wrong secret", "other", "s3cret", ciscospark.ErrInvalidSignature},
+		{"unsigned", "", "s3cret", ciscospark.ErrMissingSignature},
+	}
+	for _, tt := range tests {
+		secret = tt.secret
+		message := &ciscospark.Message{ID: "m1", RoomID: "r1"}
+		if err := s.Fire(target.URL, tt.signed, "messages", "created", message); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		res := <-results
+		if res.verified != tt.want {
+			t.Errorf("%s: error = %v, want %v", tt.name, res.verified, tt.want)
+		}
+		if len(res.body) == 0 || string(res.body) != string(res.reread) {
+			t.Errorf("%s: body was not put back on the request", tt.name)
+		}
+	}
+}
+
+func TestValidSignature(t *testing.T) {
+	body := []byte(`{"id":"w1"}`)
+	signature := ciscospark.Signature("s3cret", body)
+	if !ciscospark.ValidSignature("s3cret", body, signature) {
+		t.Error("signature of the body is not valid")
+	}
+	if ciscospark.ValidSignature("s3cret", []byte(`{"id":"w2"}`), signature) {
+		t.Error("signature of another body is valid")
+	}
+	if ciscospark.ValidSignature("s3cret", body, "not hex") {
+		t.Error("malformed signature is valid")
+	}
+}
@@ -0,0 +1,866 @@
+// Package sparktest provides an in-memory fake of the Cisco Spark REST API
+// for testing bots and ciscospark clients offline.
+package sparktest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	ciscospark ".."
+)
+
+// DefaultPageSize is the page size used when a list call does not set max.
+const DefaultPageSize = 100
+
+const timeFormat = "2006-01-02T15:04:05.000Z"
+
+// resources maps the collections served under v1/ to the type used in their IDs.
+var resources = map[string]string{
+	"rooms":       "ROOM",
+	"messages":    "MESSAGE",
+	"people":      "PEOPLE",
+	"memberships": "MEMBERSHIP",
+	"webhooks":    "WEBHOOK",
+	"teams":       "TEAM",
+
+	"attachmentActions": "ATTACHMENT_ACTION",
+	"events":            "EVENT",
+	"roomTabs":          "ROOM_TAB",
+
+	"resourceGroupMemberships": "RESOURCE_GROUP_MEMBERSHIP",
+}
+
+// listParams are query parameters that control listing rather than filter items.
+var listParams = map[string]bool{"max": true, "cursor": true, "callingData": true, "sortBy": true}
+
+type object map[string]interface{}
+
+type content struct {
+	name        string
+	contentType string
+	data        []byte
+}
+
+type collection struct {
+	order []string
+	items map[string]object
+}
+
+// Server is a fake Cisco Spark API backed by in-memory state. It is safe for
+// concurrent use.
+type Server struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	seq         int
+	collections map[string]*collection
+	contents    map[string]*content
+	me          object
+	rateLimited int
+	failures    int
+	failStatus  int
+	failMessage string
+	revoked     map[string]bool
+	retryAfter  time.Duration
+}
+
+// NewServer starts a fake Spark API. The token owner, returned by people/me
+// and used as the author of posted messages, is me. Close the server when done.
+func NewServer(me *ciscospark.Person) *Server {
+	s := &Server{
+		collections: make(map[string]*collection),
+		contents:    make(map[string]*content),
+		revoked:     make(map[string]bool),
+	}
+	for name := range resources {
+		s.collections[name] = &collection{items: make(map[string]object)}
+	}
+	if me == nil {
+		me = &ciscospark.Person{DisplayName: "Sparkbot", Emails: []string{"sparkbot@sparkbot.io"}, Type: "bot"}
+	}
+	s.me = s.insert("people", toObject(me))
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Client returns a ciscospark client pointed at the fake server.
+func (s *Server) Client(opts ...ciscospark.ClientOpt) *ciscospark.Client {
+	opts = append([]ciscospark.ClientOpt{ciscospark.SetBaseURL(s.URL + "/")}, opts...)
+	c, err := ciscospark.New(s.Server.Client(), opts...)
+	if err != nil {
+		panic(err)
+	}
+	c.Authorization = "Bearer sparktest"
+	return c
+}
+
+// Me returns the identity of the token owner.
+func (s *Server) Me() *ciscospark.Person {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := new(ciscospark.Person)
+	fromObject(s.me, p)
+	return p
+}
+
+// RevokeToken makes requests authorized with the access token fail with 401.
+func (s *Server) RevokeToken(accessToken string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.revoked[accessToken] = true
+}
+
+// RateLimit makes the next n API requests fail with 429 Too Many Requests and
+// the given Retry-After.
+func (s *Server) RateLimit(n int, retryAfter time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rateLimited = n
+	s.retryAfter = retryAfter
+}
+
+// Fail makes the next n API requests fail with the given status code and
+// error message, e.g. to test how a client handles 403 or 503 responses.
+func (s *Server) Fail(n int, status int, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = n
+	s.failStatus = status
+	s.failMessage = message
+}
+
+// AddPerson stores a person and returns it with its ID set.
+func (s *Server) AddPerson(p *ciscospark.Person) *ciscospark.Person {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := new(ciscospark.Person)
+	fromObject(s.insert("people", toObject(p)), out)
+	return out
+}
+
+// AddRoom stores a room, with the token owner as a member, and returns it with its ID set.
+func (s *Server) AddRoom(r *ciscospark.Room) *ciscospark.Room {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := new(ciscospark.Room)
+	fromObject(s.createRoom(toObject(r)), out)
+	return out
+}
+
+// AddMessage stores a message as if it was posted by its PersonID or
+// PersonEmail and returns it with its ID set. The message shows in the
+// events list but no webhook is fired.
+func (s *Server) AddMessage(m *ciscospark.Message) *ciscospark.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj := toObject(m)
+	if person := s.findPerson(str(obj["personId"]), str(obj["personEmail"])); person != nil {
+		obj["personId"] = person["id"]
+		obj["personEmail"] = firstEmail(person)
+	}
+	if room, ok := s.collections["rooms"].items[str(obj["roomId"])]; ok {
+		obj["roomType"] = room["type"]
+	}
+	out := new(ciscospark.Message)
+	obj = s.insert("messages", obj)
+	s.recordEvent("messages", "created", str(obj["personId"]), obj)
+	fromObject(obj, out)
+	return out
+}
+
+// AddAttachmentAction stores a card submission made by its PersonID and
+// returns it with its ID set. No webhook is fired.
+func (s *Server) AddAttachmentAction(a *ciscospark.AttachmentAction) *ciscospark.AttachmentAction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj := toObject(a)
+	if str(obj["type"]) == "" {
+		obj["type"] = "submit"
+	}
+	out := new(ciscospark.AttachmentAction)
+	fromObject(s.insert("attachmentActions", obj), out)
+	return out
+}
+
+// AddResourceGroupMembership stores the resource group membership of a
+// hybrid service license and returns it with its ID set. Status defaults to
+// "pending".
+func (s *Server) AddResourceGroupMembership(m *ciscospark.ResourceGroupMembership) *ciscospark.ResourceGroupMembership {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj := toObject(m)
+	if str(obj["status"]) == "" {
+		obj["status"] = "pending"
+	}
+	out := new(ciscospark.ResourceGroupMembership)
+	fromObject(s.insert("resourceGroupMemberships", obj), out)
+	return out
+}
+
+// Messages returns every message stored in roomID, oldest first. An empty
+// roomID returns the messages of all rooms.
+func (s *Server) Messages(roomID string) []*ciscospark.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var messages []*ciscospark.Message
+	c := s.collections["messages"]
+	for _, id := range c.order {
+		if roomID != "" && str(c.items[id]["roomId"]) != roomID {
+			continue
+		}
+		m := new(ciscospark.Message)
+		fromObject(c.items[id], m)
+		messages = append(messages, m)
+	}
+	return messages
+}
+
+// Webhooks returns the registered webhooks.
+func (s *Server) Webhooks() []*ciscospark.Webhook {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var webhooks []*ciscospark.Webhook
+	c := s.collections["webhooks"]
+	for _, id := range c.order {
+		w := new(ciscospark.Webhook)
+		fromObject(c.items[id], w)
+		webhooks = append(webhooks, w)
+	}
+	return webhooks
+}
+
+// Fire posts a webhook event with data as payload to targetURL, signed with
+// secret when it is not empty.
+func (s *Server) Fire(targetURL, secret, resource, event string, data interface{}) error {
+	s.mu.Lock()
+	id := s.newID("webhooks")
+	s.mu.Unlock()
+	webhook := object{
+		"id":        id,
+		"name":      "sparktest",
+		"targetUrl": targetURL,
+		"resource":  resource,
+		"event":     event,
+		"secret":    secret,
+	}
+	return s.fire(webhook, toObject(data))
+}
+
+// FireWebhooks posts the event to every registered webhook whose resource,
+// event and filter match it.
+func (s *Server) FireWebhooks(resource, event string, data interface{}) error {
+	payload := toObject(data)
+	s.mu.Lock()
+	var matching []object
+	c := s.collections["webhooks"]
+	for _, id := range c.order {
+		w := c.items[id]
+		if (w["resource"] == resource || w["resource"] == "all") && (w["event"] == event || w["event"] == "all") &&
+			matchFilter(str(w["filter"]), payload) {
+			matching = append(matching, toObject(w))
+		}
+	}
+	s.mu.Unlock()
+
+	for _, w := range matching {
+		if err := s.fire(w, payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Server) fire(webhook object, data object) error {
+	envelope := object{}
+	for k, v := range webhook {
+		if k != "secret" {
+			envelope[k] = v
+		}
+	}
+	envelope["data"] = data
+	envelope["status"] = "active"
+	envelope["created"] = time.Now().UTC().Format(timeFormat)
+	if actor, ok := data["personId"]; ok {
+		envelope["actorId"] = actor
+	}
+	body, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", str(webhook["targetUrl"]), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if secret := str(webhook["secret"]); secret != "" {
+		req.Header.Set(ciscospark.SignatureHeader, ciscospark.Signature(secret, body))
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("sparktest: webhook %s returned %s", webhook["targetUrl"], resp.Status)
+	}
+	return nil
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.rateLimited > 0 {
+		s.rateLimited--
+		w.Header().Set("Retry-After", strconv.Itoa(int(s.retryAfter/time.Second)))
+		writeError(w, http.StatusTooManyRequests, "Too Many Requests")
+		return
+	}
+	if s.failures > 0 {
+		s.failures--
+		writeError(w, s.failStatus, s.failMessage)
+		return
+	}
+	if r.URL.Path == "/v1/access_token" && r.Method == "POST" {
+		s.issueToken(w, r)
+		return
+	}
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") || s.revoked[strings.TrimPrefix(auth, "Bearer ")] {
+		writeError(w, http.StatusUnauthorized, "The request requires a valid access token set in the Authorization request header.")
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) >= 3 && parts[1] == "attachment" && parts[2] == "actions" {
+		parts = append([]string{parts[0], "attachmentActions"}, parts[3:]...)
+	}
+	if len(parts) >= 3 && parts[1] == "room" && parts[2] == "tabs" {
+		parts = append([]string{parts[0], "roomTabs"}, parts[3:]...)
+	}
+	if len(parts) >= 3 && parts[1] == "resourceGroup" && parts[2] == "memberships" {
+		parts = append([]string{parts[0], "resourceGroupMemberships"}, parts[3:]...)
+	}
+	if len(parts) == 4 && parts[1] == "rooms" && parts[3] == "meetingInfo" && r.Method == "GET" {
+		s.serveMeetingInfo(w, parts[2])
+		return
+	}
+	if len(parts) < 2 || len(parts) > 3 || parts[0] != "v1" {
+		writeError(w, http.StatusNotFound, "The requested resource could not be found.")
+		return
+	}
+	if len(parts) == 3 && parts[1] == "contents" {
+		s.serveContent(w, r, parts[2])
+		return
+	}
+	name := parts[1]
+	if _, ok := s.collections[name]; !ok {
+		writeError(w, http.StatusNotFound, "The requested resource could not be found.")
+		return
+	}
+
+	if len(parts) == 2 {
+		switch r.Method {
+		case "GET":
+			s.list(w, r, name, r.URL.Query())
+		case "POST":
+			s.create(w, r, name)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "Method not allowed.")
+		}
+		return
+	}
+
+	id := parts[2]
+	if name == "people" && id == "me" {
+		id = str(s.me["id"])
+	}
+	if name == "messages" && id == "direct" && r.Method == "GET" {
+		s.listDirect(w, r)
+		return
+	}
+	item, ok := s.collections[name].items[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "The requested resource could not be found.")
+		return
+	}
+	switch r.Method {
+	case "GET":
+		writeJSON(w, http.StatusOK, item)
+	case "PUT":
+		var update object
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		for k, v := range update {
+			if k != "id" && k != "created" {
+				item[k] = v
+			}
+		}
+		if name == "messages" {
+			item["updated"] = time.Now().UTC().Format(timeFormat)
+		}
+		writeJSON(w, http.StatusOK, item)
+	case "DELETE":
+		s.remove(name, id)
+		if name == "messages" || name == "memberships" {
+			s.recordEvent(name, "deleted", str(s.me["id"]), item)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed.")
+	}
+}
+
+// issueToken answers the OAuth token endpoint with a new access token for
+// any authorization code or refresh token
+func (s *Server) issueToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if r.PostForm.Get("code") == "" && r.PostForm.Get("refresh_token") == "" {
+		writeError(w, http.StatusBadRequest, "code or refresh_token is required.")
+		return
+	}
+	s.seq++
+	writeJSON(w, http.StatusOK, object{
+		"access_token":  fmt.Sprintf("sparktest-%d", s.seq),
+		"token_type":    "Bearer",
+		"expires_in":    3600,
+		"refresh_token": "sparktest-refresh",
+	})
+}
+
+func (s *Server) serveMeetingInfo(w http.ResponseWriter, roomID string) {
+	room, ok := s.collections["rooms"].items[roomID]
+	if !ok {
+		writeError(w, http.StatusNotFound, "The requested resource could not be found.")
+		return
+	}
+	writeJSON(w, http.StatusOK, object{
+		"roomId":      room["id"],
+		"meetingLink": "https://meet.sparktest.local/" + roomID,
+		"sipAddress":  roomID + "@meet.sparktest.local",
+	})
+}
+
+func (s *Server) serveContent(w http.ResponseWriter, r *http.Request, id string) {
+	c, ok := s.contents[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "The requested resource could not be found.")
+		return
+	}
+	if r.Method != "GET" && r.Method != "HEAD" {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed.")
+		return
+	}
+	w.Header().Set("Content-Type", c.contentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": c.name}))
+	w.Header().Set("Content-Length", strconv.Itoa(len(c.data)))
+	w.WriteHeader(http.StatusOK)
+	if r.Method == "GET" {
+		w.Write(c.data)
+	}
+}
+
+// listDirect lists the messages of the 1:1 room with the person given by personId or personEmail.
+func (s *Server) listDirect(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	person := s.findPerson(query.Get("personId"), query.Get("personEmail"))
+	if person == nil {
+		writeError(w, http.StatusBadRequest, "personId or personEmail is required.")
+		return
+	}
+	filter := url.Values{"roomId": {str(s.directRoom(person)["id"])}}
+	if parentID := query.Get("parentId"); parentID != "" {
+		filter.Set("parentId", parentID)
+	}
+	s.list(w, r, "messages", filter)
+}
+
+// list writes a page of the items of name matching filter, with a Link header to the next page.
+func (s *Server) list(w http.ResponseWriter, r *http.Request, name string, filter url.Values) {
+	query := r.URL.Query()
+	c := s.collections[name]
+
+	var matched []object
+	for _, id := range c.order {
+		if matchQuery(name, c.items[id], filter) {
+			matched = append(matched, c.items[id])
+		}
+	}
+	if name == "messages" {
+		// Spark lists messages newest first
+		for i, j := 0, len(matched)-1; i < j; i, j = i+1, j-1 {
+			matched[i], matched[j] = matched[j], matched[i]
+		}
+	}
+
+	switch query.Get("sortBy") {
+	case "id":
+		sort.SliceStable(matched, func(i, j int) bool { return str(matched[i]["id"]) < str(matched[j]["id"]) })
+	case "lastactivity", "created":
+		// Newest first, timestamps in timeFormat sort as strings
+		key := map[string]string{"lastactivity": "lastActivity", "created": "created"}[query.Get("sortBy")]
+		sort.SliceStable(matched, func(i, j int) bool { return str(matched[i][key]) > str(matched[j][key]) })
+	}
+
+	max, _ := strconv.Atoi(query.Get("max"))
+	if max <= 0 {
+		max = DefaultPageSize
+	}
+	cursor, _ := strconv.Atoi(query.Get("cursor"))
+	if cursor < 0 || cursor > len(matched) {
+		cursor = len(matched)
+	}
+	end := cursor + max
+	if end < len(matched) {
+		next := url.URL{Scheme: "http", Host: r.Host, Path: r.URL.Path}
+		query.Set("cursor", strconv.Itoa(end))
+		next.RawQuery = query.Encode()
+		w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, next.String()))
+	} else {
+		end = len(matched)
+	}
+
+	page := matched[cursor:end]
+	if page == nil {
+		page = []object{}
+	}
+	writeJSON(w, http.StatusOK, object{"items": page})
+}
+
+func (s *Server) create(w http.ResponseWriter, r *http.Request, name string) {
+	obj, err := s.decodeBody(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	switch name {
+	case "rooms":
+		obj = s.createRoom(obj)
+	case "messages":
+		if toPerson := s.findPerson(str(obj["toPersonId"]), str(obj["toPersonEmail"])); toPerson != nil {
+			obj["roomId"] = s.directRoom(toPerson)["id"]
+		}
+		room, ok := s.collections["rooms"].items[str(obj["roomId"])]
+		if !ok {
+			writeError(w, http.StatusNotFound, "Room not found.")
+			return
+		}
+		obj["roomType"] = room["type"]
+		obj["personId"] = s.me["id"]
+		obj["personEmail"] = firstEmail(s.me)
+		obj = s.insert(name, obj)
+		s.recordEvent(name, "created", str(s.me["id"]), obj)
+	case "memberships":
+		person := s.findPerson(str(obj["personId"]), str(obj["personEmail"]))
+		if person == nil {
+			writeError(w, http.StatusNotFound, "Person not found.")
+			return
+		}
+		obj["personId"] = person["id"]
+		obj["personEmail"] = firstEmail(person)
+		obj = s.insert(name, obj)
+		s.recordEvent(name, "created", str(s.me["id"]), obj)
+	case "roomTabs":
+		room, ok := s.collections["rooms"].items[str(obj["roomId"])]
+		if !ok {
+			writeError(w, http.StatusNotFound, "Room not found.")
+			return
+		}
+		obj["roomType"] = room["type"]
+		obj["creatorId"] = s.me["id"]
+		obj = s.insert(name, obj)
+	default:
+		obj = s.insert(name, obj)
+	}
+	writeJSON(w, http.StatusOK, obj)
+}
+
+// decodeBody reads a JSON or multipart/form-data request body. Uploaded files
+// are kept in memory and referenced by a content URL in "files".
+func (s *Server) decodeBody(r *http.Request) (object, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		var obj object
+		err := json.NewDecoder(r.Body).Decode(&obj)
+		return obj, err
+	}
+
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		return nil, err
+	}
+	obj := object{}
+	for key, values := range r.MultipartForm.Value {
+		obj[key] = values[0]
+	}
+	var files []interface{}
+	for _, headers := range r.MultipartForm.File {
+		for _, fh := range headers {
+			f, err := fh.Open()
+			if err != nil {
+				return nil, err
+			}
+			data, err := ioutil.ReadAll(f)
+			f.Close()
+			if err != nil {
+				return nil, err
+			}
+			s.seq++
+			id := strconv.Itoa(s.seq)
+			s.contents[id] = &content{
+				name:        fh.Filename,
+				contentType: fh.Header.Get("Content-Type"),
+				data:        data,
+			}
+			files = append(files, "http://"+r.Host+"/v1/contents/"+id)
+		}
+	}
+	if files != nil {
+		obj["files"] = files
+	}
+	return obj, nil
+}
+
+func (s *Server) createRoom(obj object) object {
+	if obj["type"] == nil {
+		obj["type"] = "group"
+	}
+	obj["isLocked"] = false
+	obj["creatorId"] = s.me["id"]
+	obj["lastActivity"] = time.Now().UTC().Format(timeFormat)
+	obj = s.insert("rooms", obj)
+	s.insert("memberships", object{
+		"roomId":      obj["id"],
+		"personId":    s.me["id"],
+		"personEmail": firstEmail(s.me),
+	})
+	return obj
+}
+
+// directRoom returns the 1:1 room between the token owner and person, creating it if needed.
+func (s *Server) directRoom(person object) object {
+	c := s.collections["memberships"]
+	for _, id := range c.order {
+		m := c.items[id]
+		if m["personId"] != person["id"] {
+			continue
+		}
+		if room := s.collections["rooms"].items[str(m["roomId"])]; room != nil && room["type"] == "direct" {
+			return room
+		}
+	}
+	room := s.createRoom(object{"type": "direct", "title": person["displayName"]})
+	s.insert("memberships", object{
+		"roomId":      room["id"],
+		"personId":    person["id"],
+		"personEmail": firstEmail(person),
+	})
+	return room
+}
+
+func (s *Server) findPerson(id, email string) object {
+	c := s.collections["people"]
+	if id != "" {
+		return c.items[id]
+	}
+	if email == "" {
+		return nil
+	}
+	for _, pid := range c.order {
+		if hasEmail(c.items[pid], email) {
+			return c.items[pid]
+		}
+	}
+	person := s.insert("people", object{"emails": []interface{}{email}, "displayName": email, "type": "person"})
+	return person
+}
+
+// recordEvent adds an entry to the events list for a change made to data
+func (s *Server) recordEvent(resource, typ, actorID string, data object) {
+	copied := make(object, len(data))
+	for k, v := range data {
+		copied[k] = v
+	}
+	s.insert("events", object{
+		"resource": resource,
+		"type":     typ,
+		"actorId":  actorID,
+		"data":     copied,
+	})
+}
+
+func (s *Server) insert(name string, obj object) object {
+	c := s.collections[name]
+	if str(obj["id"]) == "" {
+		obj["id"] = s.newID(name)
+	}
+	if str(obj["created"]) == "" {
+		obj["created"] = time.Now().UTC().Format(timeFormat)
+	}
+	id := str(obj["id"])
+	if _, ok := c.items[id]; !ok {
+		c.order = append(c.order, id)
+	}
+	c.items[id] = obj
+	return obj
+}
+
+func (s *Server) remove(name, id string) {
+	c := s.collections[name]
+	delete(c.items, id)
+	for i, oid := range c.order {
+		if oid == id {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+}
+
+func (s *Server) newID(name string) string {
+	s.seq++
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("ciscospark://us/%s/%d", resources[name], s.seq)))
+}
+
+// matchQuery reports whether obj matches the filtering parameters of a list call.
+func matchQuery(name string, obj object, query url.Values) bool {
+	for key := range query {
+		if listParams[key] {
+			continue
+		}
+		want := query.Get(key)
+		switch {
+		case name == "people" && key == "email":
+			if !hasEmail(obj, want) {
+				return false
+			}
+		case name == "people" && key == "displayName":
+			if !strings.HasPrefix(strings.ToLower(str(obj[key])), strings.ToLower(want)) {
+				return false
+			}
+		case name == "events" && (key == "from" || key == "to"):
+			at, err1 := time.Parse(time.RFC3339Nano, want)
+			created, err2 := time.Parse(time.RFC3339Nano, str(obj["created"]))
+			if err1 != nil || err2 != nil || (key == "from" && created.Before(at)) || (key == "to" && !created.Before(at)) {
+				return false
+			}
+		case name == "resourceGroupMemberships" && key == "license":
+			if str(obj["licenseId"]) != want {
+				return false
+			}
+		case name == "people" && key == "id":
+			if !contains(strings.Split(want, ","), str(obj["id"])) {
+				return false
+			}
+		default:
+			if fmt.Sprint(obj[key]) != want {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// matchFilter reports whether data matches a webhook filter such as "roomId=abc&personEmail=x@y.z".
+func matchFilter(filter string, data object) bool {
+	if filter == "" {
+		return true
+	}
+	values, err := url.ParseQuery(filter)
+	if err != nil {
+		return false
+	}
+	for key := range values {
+		if fmt.Sprint(data[key]) != values.Get(key) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasEmail(person object, email string) bool {
+	emails, _ := person["emails"].([]interface{})
+	for _, e := range emails {
+		if strings.EqualFold(str(e), email) {
+			return true
+		}
+	}
+	return false
+}
+
+func firstEmail(person object) string {
+	emails, _ := person["emails"].([]interface{})
+	if len(emails) == 0 {
+		return ""
+	}
+	return str(emails[0])
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func str(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
+
+func toObject(v interface{}) object {
+	obj := object{}
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		panic(err)
+	}
+	return obj
+}
+
+func fromObject(obj object, v interface{}) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		panic(err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		panic(err)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	trackingID := "sparktest_" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	w.Header().Set("TrackingID", trackingID)
+	writeJSON(w, code, object{
+		"message":    message,
+		"errors":     []object{{"description": message}},
+		"trackingId": trackingID,
+	})
+}
@@ -0,0 +1,124 @@
+package sparktest
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	ciscospark ".."
+)
+
+func TestServer_Paging(t *testing.T) {
+	s := NewServer(nil)
+	defer s.Close()
+	c := s.Client()
+	ctx := context.Background()
+
+	room := s.AddRoom(&ciscospark.Room{Title: "paging"})
+	for _, text := range []string{"1", "2", "3", "4", "5"} {
+		s.AddMessage(&ciscospark.Message{RoomID: room.ID, Text: text})
+	}
+
+	it := c.Messages.List(ctx, &ciscospark.MessageQueryParams{RoomID: room.ID, Max: 2}, nil)
+	var pages [][]string
+	for it.HasNext() {
+		page, resp, err := it.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var texts []string
+		for _, m := range page {
+			texts = append(texts, m.Text)
+		}
+		pages = append(pages, texts)
+		if last := len(pages) == 3; last != (resp.NextPage == "") {
+			t.Errorf("page %d: NextPage = %q", len(pages), resp.NextPage)
+		}
+		if resp.NextPage != "" && !strings.Contains(resp.Header.Get("Link"), `rel="next"`) {
+			t.Errorf("page %d: Link = %q", len(pages), resp.Header.Get("Link"))
+		}
+	}
+	want := "[[5 4] [3 2] [1]]"
+	if got := fmt.Sprint(pages); got != want {
+		t.Errorf("pages = %s, want %s", got, want)
+	}
+
+	messages, _, err := c.Messages.GetAll(ctx, &ciscospark.MessageQueryParams{RoomID: room.ID, Max: 2}, &ciscospark.ListOptions{Limit: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 3 || messages[2].Text != "3" {
+		t.Errorf("GetAll with limit 3 returned %d messages", len(messages))
+	}
+}
+
+func TestServer_RateLimit(t *testing.T) {
+	s := NewServer(nil)
+	defer s.Close()
+	c := s.Client()
+	ctx := context.Background()
+
+	s.RateLimit(2, 3*time.Second)
+	for i := 0; i < 2; i++ {
+		_, resp, err := c.People.GetMe(ctx)
+		if err == nil {
+			t.Fatalf("request %d was not rate limited", i+1)
+		}
+		if resp.StatusCode != http.StatusTooManyRequests {
+			t.Errorf("request %d: status = %d, want 429", i+1, resp.StatusCode)
+		}
+		if got := resp.Header.Get("Retry-After"); got != "3" {
+			t.Errorf("request %d: Retry-After = %q, want 3", i+1, got)
+		}
+	}
+	if _, _, err := c.People.GetMe(ctx); err != nil {
+		t.Errorf("request after the rate limit: %v", err)
+	}
+}
+
+func TestServer_Fire(t *testing.T) {
+	s := NewServer(nil)
+	defer s.Close()
+
+	type delivery struct {
+		body      []byte
+		signature string
+	}
+	got := make(chan delivery, 1)
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		got <- delivery{body, r.Header.Get(ciscospark.SignatureHeader)}
+	}))
+	defer target.Close()
+
+	message := &ciscospark.Message{ID: "m1", RoomID: "r1"}
+	if err := s.Fire(target.URL, "s3cret", "messages", "created", message); err != nil {
+		t.Fatal(err)
+	}
+	d := <-got
+	if !ciscospark.ValidSignature("s3cret", d.body, d.signature) {
+		t.Errorf("signature %q does not match the body", d.signature)
+	}
+	event, err := ciscospark.ParseWebhookEvent(d.body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Resource != "messages" || event.Event != "created" || event.DataID() != "m1" {
+		t.Errorf("event = %s %s %s", event.Resource, event.Event, event.DataID())
+	}
+	if strings.Contains(string(d.body), "s3cret") {
+		t.Error("the secret is sent in the payload")
+	}
+
+	if err := s.Fire(target.URL, "", "messages", "created", message); err != nil {
+		t.Fatal(err)
+	}
+	if d := <-got; d.signature != "" {
+		t.Errorf("unsigned event has signature %q", d.signature)
+	}
+}
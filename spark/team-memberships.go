@@ -1,5 +1,7 @@
 package ciscospark
 
+import "context"
+
 const teamMembershipsBasePath = "v1/team/memberships"
 
 // TeamMembershipsService is an interface for interfacing with the TeamMemberships
@@ -27,12 +29,12 @@ type UpdateTeamMembershipRequest struct {
 
 // TeamMembership ...
 type TeamMembership struct {
-	ID          string `json:"id,omitempty"`
-	TeamID      string `json:"teamId,omitempty"`
-	PersonID    string `json:"personId,omitempty"`
-	PersonEmail string `json:"personEmail,omitempty"`
-	IsModerator bool   `json:"isModerator,omitempty"`
-	Created     string `json:"created,omitempty"`
+	ID          string    `json:"id,omitempty"`
+	TeamID      string    `json:"teamId,omitempty"`
+	PersonID    string    `json:"personId,omitempty"`
+	PersonEmail string    `json:"personEmail,omitempty"`
+	IsModerator bool      `json:"isModerator,omitempty"`
+	Created     Timestamp `json:"created,omitempty"`
 }
 
 type teamMembershipsRoot struct {
@@ -48,20 +50,20 @@ func (r TeamMembershipRequest) String() string {
 }
 
 // Get ....
-func (s *TeamMembershipsService) Get(queryParams *TeamMembershipQueryParams) ([]*TeamMembership, *Response, error) {
+func (s *TeamMembershipsService) Get(ctx context.Context, queryParams *TeamMembershipQueryParams) ([]*TeamMembership, *Response, error) {
 	path := teamMembershipsBasePath
 	path, err := addOptions(path, queryParams)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", path, nil)
+	req, err := s.client.NewRequest(ctx, "GET", path, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	root := new(teamMembershipsRoot)
-	resp, err := s.client.Do(req, root)
+	resp, err := s.client.Do(ctx, req, root)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -70,17 +72,30 @@ func (s *TeamMembershipsService) Get(queryParams *TeamMembershipQueryParams) ([]
 
 }
 
+// TeamMembershipsIterator iterates over the pages of a team memberships list.
+type TeamMembershipsIterator = Iterator[TeamMembership]
+
+// List returns an iterator that follows the Link header through every page of team memberships.
+func (s *TeamMembershipsService) List(ctx context.Context, queryParams *TeamMembershipQueryParams, opt *ListOptions) *TeamMembershipsIterator {
+	return newIterator[TeamMembership](ctx, s.client, teamMembershipsBasePath, queryParams, opt)
+}
+
+// GetAll returns the team memberships from every page, up to the limit set in opt.
+func (s *TeamMembershipsService) GetAll(ctx context.Context, queryParams *TeamMembershipQueryParams, opt *ListOptions) ([]*TeamMembership, *Response, error) {
+	return s.List(ctx, queryParams, opt).All()
+}
+
 // Post ....
-func (s *TeamMembershipsService) Post(teamRequest *TeamMembershipRequest) (*TeamMembership, *Response, error) {
+func (s *TeamMembershipsService) Post(ctx context.Context, teamRequest *TeamMembershipRequest) (*TeamMembership, *Response, error) {
 	path := teamMembershipsBasePath
 
-	req, err := s.client.NewRequest("POST", path, teamRequest)
+	req, err := s.client.NewRequest(ctx, "POST", path, teamRequest)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	response := new(TeamMembership)
-	resp, err := s.client.Do(req, response)
+	resp, err := s.client.Do(ctx, req, response)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -89,16 +104,16 @@ func (s *TeamMembershipsService) Post(teamRequest *TeamMembershipRequest) (*Team
 }
 
 // GetTeamMembership ....
-func (s *TeamMembershipsService) GetTeamMembership(teamID string) (*TeamMembership, *Response, error) {
+func (s *TeamMembershipsService) GetTeamMembership(ctx context.Context, teamID string) (*TeamMembership, *Response, error) {
 	path := teamMembershipsBasePath + "/" + teamID
 
-	req, err := s.client.NewRequest("GET", path, nil)
+	req, err := s.client.NewRequest(ctx, "GET", path, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	team := new(TeamMembership)
-	resp, err := s.client.Do(req, team)
+	resp, err := s.client.Do(ctx, req, team)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -107,16 +122,16 @@ func (s *TeamMembershipsService) GetTeamMembership(teamID string) (*TeamMembersh
 }
 
 // UpdateTeamMembership ....
-func (s *TeamMembershipsService) UpdateTeamMembership(teamID string, updateTeamMembershipRequest *UpdateTeamMembershipRequest) (*TeamMembership, *Response, error) {
+func (s *TeamMembershipsService) UpdateTeamMembership(ctx context.Context, teamID string, updateTeamMembershipRequest *UpdateTeamMembershipRequest) (*TeamMembership, *Response, error) {
 	path := teamMembershipsBasePath + "/" + teamID
 
-	req, err := s.client.NewRequest("PUT", path, updateTeamMembershipRequest)
+	req, err := s.client.NewRequest(ctx, "PUT", path, updateTeamMembershipRequest)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	team := new(TeamMembership)
-	resp, err := s.client.Do(req, team)
+	resp, err := s.client.Do(ctx, req, team)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -125,15 +140,15 @@ func (s *TeamMembershipsService) UpdateTeamMembership(teamID string, updateTeamM
 }
 
 // DeleteTeamMembership ....
-func (s *TeamMembershipsService) DeleteTeamMembership(teamID string) (*Response, error) {
+func (s *TeamMembershipsService) DeleteTeamMembership(ctx context.Context, teamID string) (*Response, error) {
 	path := teamMembershipsBasePath + "/" + teamID
 
-	req, err := s.client.NewRequest("DELETE", path, nil)
+	req, err := s.client.NewRequest(ctx, "DELETE", path, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := s.client.Do(req, nil)
+	resp, err := s.client.Do(ctx, req, nil)
 	if err != nil {
 		return resp, err
 	}
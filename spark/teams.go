@@ -1,5 +1,7 @@
 package ciscospark
 
+import "context"
+
 const teamsBasePath = "v1/teams"
 
 // TeamsService is an interface for interfacing with the Teams
@@ -23,9 +25,9 @@ type UpdateTeamRequest struct {
 
 // Team ...
 type Team struct {
-	ID      string `json:"id,omitempty"`
-	Name    string `json:"name,omitempty"`
-	Created string `json:"created,omitempty"`
+	ID      string    `json:"id,omitempty"`
+	Name    string    `json:"name,omitempty"`
+	Created Timestamp `json:"created,omitempty"`
 }
 
 type teamsRoot struct {
@@ -41,20 +43,20 @@ func (r TeamRequest) String() string {
 }
 
 // Get ....
-func (s *TeamsService) Get(queryParams *TeamQueryParams) ([]*Team, *Response, error) {
+func (s *TeamsService) Get(ctx context.Context, queryParams *TeamQueryParams) ([]*Team, *Response, error) {
 	path := teamsBasePath
 	path, err := addOptions(path, queryParams)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", path, nil)
+	req, err := s.client.NewRequest(ctx, "GET", path, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	root := new(teamsRoot)
-	resp, err := s.client.Do(req, root)
+	resp, err := s.client.Do(ctx, req, root)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -63,17 +65,30 @@ func (s *TeamsService) Get(queryParams *TeamQueryParams) ([]*Team, *Response, er
 
 }
 
+// TeamsIterator iterates over the pages of a teams list.
+type TeamsIterator = Iterator[Team]
+
+// List returns an iterator that follows the Link header through every page of teams.
+func (s *TeamsService) List(ctx context.Context, queryParams *TeamQueryParams, opt *ListOptions) *TeamsIterator {
+	return newIterator[Team](ctx, s.client, teamsBasePath, queryParams, opt)
+}
+
+// GetAll returns the teams from every page, up to the limit set in opt.
+func (s *TeamsService) GetAll(ctx context.Context, queryParams *TeamQueryParams, opt *ListOptions) ([]*Team, *Response, error) {
+	return s.List(ctx, queryParams, opt).All()
+}
+
 // Post ....
-func (s *TeamsService) Post(teamRequest *TeamRequest) (*Team, *Response, error) {
+func (s *TeamsService) Post(ctx context.Context, teamRequest *TeamRequest) (*Team, *Response, error) {
 	path := teamsBasePath
 
-	req, err := s.client.NewRequest("POST", path, teamRequest)
+	req, err := s.client.NewRequest(ctx, "POST", path, teamRequest)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	response := new(Team)
-	resp, err := s.client.Do(req, response)
+	resp, err := s.client.Do(ctx, req, response)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -82,16 +97,16 @@ func (s *TeamsService) Post(teamRequest *TeamRequest) (*Team, *Response, error)
 }
 
 // GetTeam ....
-func (s *TeamsService) GetTeam(teamID string) (*Team, *Response, error) {
+func (s *TeamsService) GetTeam(ctx context.Context, teamID string) (*Team, *Response, error) {
 	path := teamsBasePath + "/" + teamID
 
-	req, err := s.client.NewRequest("GET", path, nil)
+	req, err := s.client.NewRequest(ctx, "GET", path, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	team := new(Team)
-	resp, err := s.client.Do(req, team)
+	resp, err := s.client.Do(ctx, req, team)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -100,16 +115,16 @@ func (s *TeamsService) GetTeam(teamID string) (*Team, *Response, error) {
 }
 
 // UpdateTeam ....
-func (s *TeamsService) UpdateTeam(teamID string, updateTeamRequest *UpdateTeamRequest) (*Team, *Response, error) {
+func (s *TeamsService) UpdateTeam(ctx context.Context, teamID string, updateTeamRequest *UpdateTeamRequest) (*Team, *Response, error) {
 	path := teamsBasePath + "/" + teamID
 
-	req, err := s.client.NewRequest("PUT", path, updateTeamRequest)
+	req, err := s.client.NewRequest(ctx, "PUT", path, updateTeamRequest)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	team := new(Team)
-	resp, err := s.client.Do(req, team)
+	resp, err := s.client.Do(ctx, req, team)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -118,15 +133,15 @@ func (s *TeamsService) UpdateTeam(teamID string, updateTeamRequest *UpdateTeamRe
 }
 
 // DeleteTeam ....
-func (s *TeamsService) DeleteTeam(teamID string) (*Response, error) {
+func (s *TeamsService) DeleteTeam(ctx context.Context, teamID string) (*Response, error) {
 	path := teamsBasePath + "/" + teamID
 
-	req, err := s.client.NewRequest("DELETE", path, nil)
+	req, err := s.client.NewRequest(ctx, "DELETE", path, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := s.client.Do(req, nil)
+	resp, err := s.client.Do(ctx, req, nil)
 	if err != nil {
 		return resp, err
 	}
@@ -17,18 +17,33 @@ func (t Timestamp) String() string {
 }
 
 // UnmarshalJSON implements the json.Unmarshaler interface.
-// Time is expected in RFC3339 or Unix format.
+// Time is expected in RFC3339 or Unix format, fractional seconds such as
+// 2017-09-25T10:13:14.729Z are kept. A null or empty value is the zero time.
 func (t *Timestamp) UnmarshalJSON(data []byte) error {
 	str := string(data)
+	if str == "null" || str == `""` {
+		t.Time = time.Time{}
+		return nil
+	}
 	i, err := strconv.ParseInt(str, 10, 64)
 	if err == nil {
 		t.Time = time.Unix(i, 0)
 	} else {
-		t.Time, err = time.Parse(`"`+time.RFC3339+`"`, str)
+		t.Time, err = time.Parse(`"`+time.RFC3339Nano+`"`, str)
 	}
 	return err
 }
 
+// MarshalJSON implements the json.Marshaler interface.
+// The time is written in RFC3339 format with fractional seconds, or as null
+// when it is the zero time.
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.Time.IsZero() {
+		return []byte("null"), nil
+	}
+	return []byte(`"` + t.Time.Format(time.RFC3339Nano) + `"`), nil
+}
+
 // Equal reports whether t and u are equal based on time.Equal
 func (t Timestamp) Equal(u Timestamp) bool {
 	return t.Time.Equal(u.Time)
@@ -0,0 +1,85 @@
+package ciscospark_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	ciscospark "."
+	"./sparktest"
+)
+
+func TestTimestamp_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{`"2017-09-25T10:13:14.729Z"`, time.Date(2017, 9, 25, 10, 13, 14, 729000000, time.UTC)},
+		{`"2017-09-25T10:13:14Z"`, time.Date(2017, 9, 25, 10, 13, 14, 0, time.UTC)},
+		{`1506334394`, time.Unix(1506334394, 0)},
+		{`null`, time.Time{}},
+		{`""`, time.Time{}},
+	}
+	for _, tt := range tests {
+		var ts ciscospark.Timestamp
+		if err := json.Unmarshal([]byte(tt.in), &ts); err != nil {
+			t.Errorf("%s: %v", tt.in, err)
+			continue
+		}
+		if !ts.Time.Equal(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.in, ts.Time, tt.want)
+		}
+	}
+}
+
+func TestTimestamp_RoundTrip(t *testing.T) {
+	for _, in := range []string{`"2017-09-25T10:13:14.729Z"`, `null`} {
+		var ts ciscospark.Timestamp
+		if err := json.Unmarshal([]byte(in), &ts); err != nil {
+			t.Fatal(err)
+		}
+		out, err := json.Marshal(ts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(out) != in {
+			t.Errorf("%s round-tripped to %s", in, out)
+		}
+	}
+}
+
+func TestTimestamp_RoundTripThroughAPI(t *testing.T) {
+	s := sparktest.NewServer(nil)
+	defer s.Close()
+	c := s.Client()
+	ctx := context.Background()
+	room := s.AddRoom(&ciscospark.Room{Title: "timestamps"})
+
+	posted, _, err := c.Messages.Post(ctx, &ciscospark.MessageRequest{RoomID: room.ID, Text: "now"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if posted.Created.IsZero() || time.Since(posted.Created.Time) > time.Minute {
+		t.Fatalf("Created = %v, want the time of posting", posted.Created)
+	}
+	fetched, _, err := c.Messages.GetMessage(ctx, posted.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fetched.Created.Equal(posted.Created) {
+		t.Errorf("fetched Created = %v, posted %v", fetched.Created, posted.Created)
+	}
+
+	data, err := json.Marshal(fetched)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := new(ciscospark.Message)
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.Created.Equal(fetched.Created) || !decoded.Updated.IsZero() {
+		t.Errorf("re-decoded Created = %v, Updated = %v", decoded.Created, decoded.Updated)
+	}
+}
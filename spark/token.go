@@ -0,0 +1,276 @@
+package ciscospark
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	authorizeURL   = "https://api.ciscospark.com/v1/authorize"
+	accessTokenURL = "https://api.ciscospark.com/v1/access_token"
+
+	// expiryDelta is how long before its expiry a token is refreshed
+	expiryDelta = time.Minute
+)
+
+// ErrNoToken is returned when a token source has no token to give yet, such
+// as an OAuth integration that was not authorized.
+var ErrNoToken = errors.New("ciscospark: no access token available")
+
+// Token is an access token for the Cisco Spark API
+type Token struct {
+	AccessToken  string    `json:"access_token"`
+	TokenType    string    `json:"token_type,omitempty"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	Expiry       time.Time `json:"expiry,omitempty"`
+}
+
+// Type returns the authorization scheme of the token, Bearer by default.
+func (t *Token) Type() string {
+	if t.TokenType == "" {
+		return "Bearer"
+	}
+	return t.TokenType
+}
+
+// Valid reports whether the token is set and not about to expire.
+func (t *Token) Valid() bool {
+	return t != nil && t.AccessToken != "" && (t.Expiry.IsZero() || time.Now().Add(expiryDelta).Before(t.Expiry))
+}
+
+// TokenSource supplies the token each request is authorized with.
+type TokenSource interface {
+	Token(ctx context.Context) (*Token, error)
+}
+
+// RefreshableTokenSource is a TokenSource that can get a new token when the
+// API rejects the current one.
+type RefreshableTokenSource interface {
+	TokenSource
+	// Refresh returns a token to use instead of rejected, the token a request
+	// was refused with. A source that already replaced rejected, e.g. for a
+	// concurrent request, returns its current token without refreshing again.
+	Refresh(ctx context.Context, rejected *Token) (*Token, error)
+}
+
+// SetTokenSource is a client option for authorizing requests with the tokens
+// of ts instead of the static Authorization header. A request rejected with a
+// 401 is retried once with a refreshed token when ts is a RefreshableTokenSource.
+func SetTokenSource(ts TokenSource) ClientOpt {
+	return func(c *Client) error {
+		c.tokenSource = ts
+		return nil
+	}
+}
+
+// authorize sets the Authorization header of req from the client's token
+// source and returns the token used.
+func (c *Client) authorize(req *http.Request) (*Token, error) {
+	token, err := c.tokenSource.Token(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", token.Type()+" "+token.AccessToken)
+	return token, nil
+}
+
+type staticTokenSource struct {
+	token *Token
+}
+
+// StaticTokenSource returns a TokenSource that always returns the given access token.
+func StaticTokenSource(accessToken string) TokenSource {
+	return staticTokenSource{&Token{AccessToken: accessToken}}
+}
+
+func (s staticTokenSource) Token(ctx context.Context) (*Token, error) {
+	if s.token.AccessToken == "" {
+		return nil, ErrNoToken
+	}
+	return s.token, nil
+}
+
+type envTokenSource struct {
+	name string
+}
+
+// EnvTokenSource returns a TokenSource reading the access token from the
+// environment variable name on every request.
+func EnvTokenSource(name string) TokenSource {
+	return envTokenSource{name}
+}
+
+func (s envTokenSource) Token(ctx context.Context) (*Token, error) {
+	v := os.Getenv(s.name)
+	if v == "" {
+		return nil, fmt.Errorf("ciscospark: environment variable %s is not set", s.name)
+	}
+	return &Token{AccessToken: v}, nil
+}
+
+// OAuthConfig describes a Spark integration
+type OAuthConfig struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+
+	// AuthURL and TokenURL default to the Spark endpoints
+	AuthURL  string
+	TokenURL string
+
+	// HTTPClient is used to get tokens, http.DefaultClient when nil
+	HTTPClient *http.Client
+}
+
+// AuthCodeURL returns the URL users grant the integration access at. Spark
+// redirects them to RedirectURL with a code and state.
+func (c *OAuthConfig) AuthCodeURL(state string) string {
+	u := c.AuthURL
+	if u == "" {
+		u = authorizeURL
+	}
+	v := url.Values{
+		"client_id":     {c.ClientID},
+		"response_type": {"code"},
+		"redirect_uri":  {c.RedirectURL},
+		"scope":         {strings.Join(c.Scopes, " ")},
+		"state":         {state},
+	}
+	return u + "?" + v.Encode()
+}
+
+// Exchange trades the code of an authorization grant for a token.
+func (c *OAuthConfig) Exchange(ctx context.Context, code string) (*Token, error) {
+	return c.retrieveToken(ctx, url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {c.RedirectURL},
+	})
+}
+
+func (c *OAuthConfig) refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	return c.retrieveToken(ctx, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	})
+}
+
+func (c *OAuthConfig) retrieveToken(ctx context.Context, v url.Values) (*Token, error) {
+	u := c.TokenURL
+	if u == "" {
+		u = accessTokenURL
+	}
+	v.Set("client_id", c.ClientID)
+	v.Set("client_secret", c.ClientSecret)
+	req, err := http.NewRequest("POST", u, strings.NewReader(v.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", mediaType)
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if err := CheckResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var body struct {
+		AccessToken  string `json:"access_token"`
+		TokenType    string `json:"token_type"`
+		ExpiresIn    int64  `json:"expires_in"`
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	if body.AccessToken == "" {
+		return nil, errors.New("ciscospark: token response has no access token")
+	}
+	token := &Token{AccessToken: body.AccessToken, TokenType: body.TokenType, RefreshToken: body.RefreshToken}
+	if body.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(body.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
+
+// OAuthTokenSource returns the token of an integration, refreshing it with
+// its refresh token once expired or rejected. It is safe for concurrent use.
+type OAuthTokenSource struct {
+	config *OAuthConfig
+
+	// OnRefresh, when set, is called with every new token so it can be stored
+	OnRefresh func(*Token)
+
+	mu    sync.Mutex
+	token *Token
+}
+
+// NewOAuthTokenSource returns a token source for config starting with token,
+// which may be nil until the integration is authorized.
+func NewOAuthTokenSource(config *OAuthConfig, token *Token) *OAuthTokenSource {
+	return &OAuthTokenSource{config: config, token: token}
+}
+
+// SetToken replaces the token, e.g. after an authorization grant.
+func (s *OAuthTokenSource) SetToken(token *Token) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = token
+}
+
+// Token returns the current token, refreshed first when it expired.
+func (s *OAuthTokenSource) Token(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token.Valid() {
+		return s.token, nil
+	}
+	return s.refresh(ctx)
+}
+
+// Refresh gets a new token with the refresh token, unless the current token
+// already differs from rejected and is still valid.
+func (s *OAuthTokenSource) Refresh(ctx context.Context, rejected *Token) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if rejected != nil && s.token.Valid() && s.token.AccessToken != rejected.AccessToken {
+		return s.token, nil
+	}
+	return s.refresh(ctx)
+}
+
+func (s *OAuthTokenSource) refresh(ctx context.Context) (*Token, error) {
+	if s.token == nil || s.token.RefreshToken == "" {
+		return nil, ErrNoToken
+	}
+	token, err := s.config.refresh(ctx, s.token.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if token.RefreshToken == "" {
+		token.RefreshToken = s.token.RefreshToken
+	}
+	s.token = token
+	if s.OnRefresh != nil {
+		s.OnRefresh(token)
+	}
+	return token, nil
+}
@@ -0,0 +1,137 @@
+package ciscospark_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	ciscospark "."
+	"./sparktest"
+)
+
+// countingTransport counts the requests sent to the token endpoint
+type countingTransport struct {
+	mu       sync.Mutex
+	requests int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	t.requests++
+	t.mu.Unlock()
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func (t *countingTransport) count() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.requests
+}
+
+func newRefreshingSource(s *sparktest.Server) (*ciscospark.OAuthTokenSource, *countingTransport) {
+	transport := new(countingTransport)
+	ts := ciscospark.NewOAuthTokenSource(&ciscospark.OAuthConfig{
+		ClientID:     "id",
+		ClientSecret: "secret",
+		TokenURL:     s.URL + "/v1/access_token",
+		HTTPClient:   &http.Client{Transport: transport},
+	}, &ciscospark.Token{AccessToken: "sparktest", RefreshToken: "refresh", Expiry: time.Now().Add(time.Hour)})
+	return ts, transport
+}
+
+func TestOAuthTokenSource_RefreshOn401(t *testing.T) {
+	s := sparktest.NewServer(nil)
+	defer s.Close()
+	ctx := context.Background()
+	ts, transport := newRefreshingSource(s)
+	var refreshed []*ciscospark.Token
+	ts.OnRefresh = func(token *ciscospark.Token) { refreshed = append(refreshed, token) }
+	c := s.Client(ciscospark.SetTokenSource(ts))
+	var sent []string
+	c.OnRequestCompleted(func(req *http.Request, resp *http.Response) {
+		sent = append(sent, req.Header.Get("Authorization"))
+	})
+
+	s.RevokeToken("sparktest")
+	me, resp, err := c.People.GetMe(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if me.ID != s.Me().ID || resp.Attempts != 2 {
+		t.Errorf("GetMe returned %s after %d attempts", me.ID, resp.Attempts)
+	}
+	if transport.count() != 1 || len(refreshed) != 1 {
+		t.Fatalf("%d token requests and %d OnRefresh calls, want 1 of each", transport.count(), len(refreshed))
+	}
+	if refreshed[0].AccessToken == "sparktest" || refreshed[0].RefreshToken == "" {
+		t.Errorf("refreshed token = %+v", refreshed[0])
+	}
+	if len(sent) != 2 || sent[0] != "Bearer sparktest" || sent[1] != "Bearer "+refreshed[0].AccessToken {
+		t.Errorf("Authorization headers = %q", sent)
+	}
+
+	// a rejected refreshed token is not refreshed in a loop
+	s.RevokeToken(refreshed[0].AccessToken)
+	if _, _, err := c.People.GetMe(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if transport.count() != 2 {
+		t.Errorf("%d token requests after a second revocation, want 2", transport.count())
+	}
+}
+
+func TestOAuthTokenSource_ConcurrentRefresh(t *testing.T) {
+	s := sparktest.NewServer(nil)
+	defer s.Close()
+	ctx := context.Background()
+	ts, transport := newRefreshingSource(s)
+	c := s.Client(ciscospark.SetTokenSource(ts))
+
+	// requests sent before the first refresh all get a 401
+	stale, err := ts.Token(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.RevokeToken(stale.AccessToken)
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := c.People.GetMe(ctx)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	if transport.count() != 1 {
+		t.Errorf("%d token requests for concurrent 401s, want 1", transport.count())
+	}
+
+	if _, err := ts.Refresh(ctx, stale); err != nil || transport.count() != 1 {
+		t.Errorf("Refresh of a replaced token: %v after %d token requests, want no new request", err, transport.count())
+	}
+	if _, err := ts.Refresh(ctx, nil); err != nil || transport.count() != 2 {
+		t.Errorf("Refresh without a rejected token: %v after %d token requests, want a new request", err, transport.count())
+	}
+}
+
+func TestOAuthTokenSource_NoRefreshToken(t *testing.T) {
+	s := sparktest.NewServer(nil)
+	defer s.Close()
+	ts := ciscospark.NewOAuthTokenSource(&ciscospark.OAuthConfig{TokenURL: s.URL + "/v1/access_token"}, &ciscospark.Token{AccessToken: "sparktest"})
+	c := s.Client(ciscospark.SetTokenSource(ts))
+
+	s.RevokeToken("sparktest")
+	if _, _, err := c.People.GetMe(context.Background()); !ciscospark.IsUnauthorized(err) {
+		t.Errorf("error = %v, want unauthorized", err)
+	}
+}
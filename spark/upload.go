@@ -0,0 +1,122 @@
+package ciscospark
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+// FileUpload is a local file sent with a request as multipart/form-data
+type FileUpload struct {
+	// Name is the file name shown in Spark
+	Name string
+
+	// ContentType of the file, detected from the Name extension when empty
+	ContentType string
+
+	// Reader supplies the file content
+	Reader io.Reader
+}
+
+// FileFromPath reads the file at path into a FileUpload.
+func FileFromPath(path string) (*FileUpload, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &FileUpload{Name: filepath.Base(path), Reader: bytes.NewReader(data)}, nil
+}
+
+func (f FileUpload) String() string {
+	return fmt.Sprintf("ciscospark.FileUpload{Name:%q, ContentType:%q}", f.Name, f.ContentType)
+}
+
+// NewMultipartRequest creates an API request with a multipart/form-data body. The fields of the JSON encoding
+// of body are sent as form fields, and file is sent in the "files" part. Only string fields can be sent this
+// way, an error is returned for any other field, such as Files or Attachments of a MessageRequest.
+func (c *Client) NewMultipartRequest(ctx context.Context, method, urlStr string, body interface{}, file *FileUpload) (*http.Request, error) {
+	rel, err := url.Parse(urlStr)
+	if err != nil {
+		return nil, err
+	}
+
+	u := c.BaseURL.ResolveReference(rel)
+
+	fields := make(map[string]interface{})
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return nil, err
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	w := multipart.NewWriter(buf)
+	for name, value := range fields {
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("ciscospark: %s can't be sent with a file upload", name)
+		}
+		if err := w.WriteField(name, s); err != nil {
+			return nil, err
+		}
+	}
+	if file != nil {
+		if file.Reader == nil {
+			return nil, fmt.Errorf("ciscospark: file upload %q has no reader", file.Name)
+		}
+		contentType := file.ContentType
+		if contentType == "" {
+			contentType = mime.TypeByExtension(filepath.Ext(file.Name))
+		}
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="files"; filename="%s"`, escapeQuotes(file.Name)))
+		h.Set("Content-Type", contentType)
+		part, err := w.CreatePart(h)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.Copy(part, file.Reader); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(method, u.String(), buf)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	req.Header.Add("Content-Type", w.FormDataContentType())
+	req.Header.Add("Accept", mediaType)
+	req.Header.Add("User-Agent", c.UserAgent)
+	if c.Authorization != "" {
+		req.Header.Add("Authorization", c.Authorization)
+	}
+	return req, nil
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
@@ -0,0 +1,50 @@
+package ciscospark_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	ciscospark "."
+	"./sparktest"
+)
+
+func TestMessagesService_PostUploadRejectsOtherFiles(t *testing.T) {
+	s := sparktest.NewServer(nil)
+	defer s.Close()
+	c := s.Client()
+	room := s.AddRoom(&ciscospark.Room{Title: "upload"})
+
+	tests := map[string]*ciscospark.MessageRequest{
+		"files":       {Files: []string{"https://example.com/report.csv"}},
+		"attachments": {Attachments: []ciscospark.Attachment{{ContentType: "application/vnd.microsoft.card.adaptive"}}},
+	}
+	for name, req := range tests {
+		req.RoomID = room.ID
+		req.Upload = &ciscospark.FileUpload{Name: "report.csv", Reader: strings.NewReader("a,b\n")}
+		if _, _, err := c.Messages.Post(context.Background(), req); err == nil || !strings.Contains(err.Error(), name) {
+			t.Errorf("%s: error = %v, want one naming %s", name, err, name)
+		}
+	}
+	if messages := s.Messages(room.ID); len(messages) != 0 {
+		t.Errorf("%d partial messages were posted", len(messages))
+	}
+}
+
+func TestMessagesService_PostUploadWithoutReader(t *testing.T) {
+	s := sparktest.NewServer(nil)
+	defer s.Close()
+	c := s.Client()
+	room := s.AddRoom(&ciscospark.Room{Title: "upload"})
+
+	_, _, err := c.Messages.Post(context.Background(), &ciscospark.MessageRequest{
+		RoomID: room.ID,
+		Upload: &ciscospark.FileUpload{Name: "report.csv"},
+	})
+	if err == nil {
+		t.Fatal("upload without a reader was sent")
+	}
+	if messages := s.Messages(room.ID); len(messages) != 0 {
+		t.Errorf("%d messages were posted", len(messages))
+	}
+}
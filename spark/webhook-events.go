@@ -0,0 +1,156 @@
+package ciscospark
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Webhook resources
+const (
+	ResourceMessages          = "messages"
+	ResourceMemberships       = "memberships"
+	ResourceRooms             = "rooms"
+	ResourceAttachmentActions = "attachmentActions"
+)
+
+// Webhook events
+const (
+	EventCreated = "created"
+	EventUpdated = "updated"
+	EventDeleted = "deleted"
+	EventAll     = "all"
+)
+
+// WebhookEvent is the notification Spark posts to the target URL of a webhook.
+// Data holds a *Message, *Membership, *Room or *AttachmentAction depending on
+// Resource, or the raw JSON for resources without a typed payload.
+type WebhookEvent struct {
+	ID        string      `json:"id,omitempty"`
+	Name      string      `json:"name,omitempty"`
+	TargetURL string      `json:"targetUrl,omitempty"`
+	Resource  string      `json:"resource,omitempty"`
+	Event     string      `json:"event,omitempty"`
+	Filter    string      `json:"filter,omitempty"`
+	OrgID     string      `json:"orgId,omitempty"`
+	CreatedBy string      `json:"createdBy,omitempty"`
+	AppID     string      `json:"appId,omitempty"`
+	OwnedBy   string      `json:"ownedBy,omitempty"`
+	Status    string      `json:"status,omitempty"`
+	Created   Timestamp   `json:"created,omitempty"`
+	ActorID   string      `json:"actorId,omitempty"`
+	Data      interface{} `json:"data,omitempty"`
+}
+
+func (r WebhookEvent) String() string {
+	return Stringify(r)
+}
+
+// ParseWebhookEvent decodes the body of a webhook notification.
+func ParseWebhookEvent(body []byte) (*WebhookEvent, error) {
+	event := new(WebhookEvent)
+	if err := json.Unmarshal(body, event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// UnmarshalJSON decodes Data into the payload type of the event resource.
+func (r *WebhookEvent) UnmarshalJSON(b []byte) error {
+	type envelope WebhookEvent
+	var raw struct {
+		*envelope
+		Data json.RawMessage `json:"data,omitempty"`
+	}
+	raw.envelope = (*envelope)(r)
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	data, err := decodeResourceData(r.Resource, raw.Data)
+	if err != nil {
+		return fmt.Errorf("ciscospark: decoding %s webhook data: %v", r.Resource, err)
+	}
+	r.Data = data
+	return nil
+}
+
+// decodeResourceData decodes the data of an event into the type of resource,
+// unknown resources are kept as json.RawMessage.
+func decodeResourceData(resource string, raw json.RawMessage) (interface{}, error) {
+	var data interface{}
+	switch resource {
+	case ResourceMessages:
+		data = new(Message)
+	case ResourceMemberships:
+		data = new(Membership)
+	case ResourceRooms:
+		data = new(Room)
+	case ResourceAttachmentActions:
+		data = new(AttachmentAction)
+	default:
+		return raw, nil
+	}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, data); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// Message returns the payload of a messages event.
+func (r *WebhookEvent) Message() (*Message, bool) {
+	m, ok := r.Data.(*Message)
+	return m, ok
+}
+
+// Membership returns the payload of a memberships event.
+func (r *WebhookEvent) Membership() (*Membership, bool) {
+	m, ok := r.Data.(*Membership)
+	return m, ok
+}
+
+// Room returns the payload of a rooms event.
+func (r *WebhookEvent) Room() (*Room, bool) {
+	m, ok := r.Data.(*Room)
+	return m, ok
+}
+
+// AttachmentAction returns the payload of an attachmentActions event.
+func (r *WebhookEvent) AttachmentAction() (*AttachmentAction, bool) {
+	m, ok := r.Data.(*AttachmentAction)
+	return m, ok
+}
+
+// DataID returns the ID of the resource the event is about.
+func (r *WebhookEvent) DataID() string {
+	switch data := r.Data.(type) {
+	case *Message:
+		return data.ID
+	case *Membership:
+		return data.ID
+	case *Room:
+		return data.ID
+	case *AttachmentAction:
+		return data.ID
+	case json.RawMessage:
+		var v struct {
+			ID string `json:"id"`
+		}
+		json.Unmarshal(data, &v)
+		return v.ID
+	}
+	return ""
+}
+
+// PersonID returns the ID of the person who posted the message or submitted
+// the card of the event, or "" for other resources.
+func (r *WebhookEvent) PersonID() string {
+	switch data := r.Data.(type) {
+	case *Message:
+		return data.PersonID
+	case *AttachmentAction:
+		return data.PersonID
+	}
+	return ""
+}
@@ -1,5 +1,7 @@
 package ciscospark
 
+import "context"
+
 const webhooksBasePath = "v1/webhooks"
 
 // WebhooksService is an interface for interfacing with the Webhooks
@@ -18,23 +20,26 @@ type WebhookRequest struct {
 	Resource  string `json:"resource,omitempty"`
 	Event     string `json:"event,omitempty"`
 	Filter    string `json:"filter,omitempty"`
+	Secret    string `json:"secret,omitempty"`
 }
 
 // UpdateWebhookRequest represents the Spark webhooks
 type UpdateWebhookRequest struct {
 	Name      string `json:"name,omitempty"`
 	TargetURL string `json:"targetUrl,omitempty"`
+	Secret    string `json:"secret,omitempty"`
 }
 
 // Webhook ...
 type Webhook struct {
-	ID        string `json:"id,omitempty"`
-	Name      string `json:"name,omitempty"`
-	TargetURL string `json:"targetUrl,omitempty"`
-	Resource  string `json:"resource,omitempty"`
-	Event     string `json:"event,omitempty"`
-	Filter    string `json:"filter,omitempty"`
-	Created   string `json:"created,omitempty"`
+	ID        string    `json:"id,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	TargetURL string    `json:"targetUrl,omitempty"`
+	Resource  string    `json:"resource,omitempty"`
+	Event     string    `json:"event,omitempty"`
+	Filter    string    `json:"filter,omitempty"`
+	Secret    string    `json:"secret,omitempty"`
+	Created   Timestamp `json:"created,omitempty"`
 }
 
 type webhooksRoot struct {
@@ -50,20 +55,20 @@ func (r WebhookRequest) String() string {
 }
 
 // Get ....
-func (s *WebhooksService) Get(queryParams *WebhookQueryParams) ([]*Webhook, *Response, error) {
+func (s *WebhooksService) Get(ctx context.Context, queryParams *WebhookQueryParams) ([]*Webhook, *Response, error) {
 	path := webhooksBasePath
 	path, err := addOptions(path, queryParams)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", path, nil)
+	req, err := s.client.NewRequest(ctx, "GET", path, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	root := new(webhooksRoot)
-	resp, err := s.client.Do(req, root)
+	resp, err := s.client.Do(ctx, req, root)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -72,17 +77,30 @@ func (s *WebhooksService) Get(queryParams *WebhookQueryParams) ([]*Webhook, *Res
 
 }
 
+// WebhooksIterator iterates over the pages of a webhooks list.
+type WebhooksIterator = Iterator[Webhook]
+
+// List returns an iterator that follows the Link header through every page of webhooks.
+func (s *WebhooksService) List(ctx context.Context, queryParams *WebhookQueryParams, opt *ListOptions) *WebhooksIterator {
+	return newIterator[Webhook](ctx, s.client, webhooksBasePath, queryParams, opt)
+}
+
+// GetAll returns the webhooks from every page, up to the limit set in opt.
+func (s *WebhooksService) GetAll(ctx context.Context, queryParams *WebhookQueryParams, opt *ListOptions) ([]*Webhook, *Response, error) {
+	return s.List(ctx, queryParams, opt).All()
+}
+
 // Post ....
-func (s *WebhooksService) Post(webhookRequest *WebhookRequest) (*Webhook, *Response, error) {
+func (s *WebhooksService) Post(ctx context.Context, webhookRequest *WebhookRequest) (*Webhook, *Response, error) {
 	path := webhooksBasePath
 
-	req, err := s.client.NewRequest("POST", path, webhookRequest)
+	req, err := s.client.NewRequest(ctx, "POST", path, webhookRequest)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	response := new(Webhook)
-	resp, err := s.client.Do(req, response)
+	resp, err := s.client.Do(ctx, req, response)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -91,16 +109,16 @@ func (s *WebhooksService) Post(webhookRequest *WebhookRequest) (*Webhook, *Respo
 }
 
 // GetWebhook ....
-func (s *WebhooksService) GetWebhook(webhookID string) (*Webhook, *Response, error) {
+func (s *WebhooksService) GetWebhook(ctx context.Context, webhookID string) (*Webhook, *Response, error) {
 	path := webhooksBasePath + "/" + webhookID
 
-	req, err := s.client.NewRequest("GET", path, nil)
+	req, err := s.client.NewRequest(ctx, "GET", path, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	webhook := new(Webhook)
-	resp, err := s.client.Do(req, webhook)
+	resp, err := s.client.Do(ctx, req, webhook)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -109,16 +127,16 @@ func (s *WebhooksService) GetWebhook(webhookID string) (*Webhook, *Response, err
 }
 
 // UpdateWebhook ....
-func (s *WebhooksService) UpdateWebhook(webhookID string, updateWebhookRequest *UpdateWebhookRequest) (*Webhook, *Response, error) {
+func (s *WebhooksService) UpdateWebhook(ctx context.Context, webhookID string, updateWebhookRequest *UpdateWebhookRequest) (*Webhook, *Response, error) {
 	path := webhooksBasePath + "/" + webhookID
 
-	req, err := s.client.NewRequest("PUT", path, updateWebhookRequest)
+	req, err := s.client.NewRequest(ctx, "PUT", path, updateWebhookRequest)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	webhook := new(Webhook)
-	resp, err := s.client.Do(req, webhook)
+	resp, err := s.client.Do(ctx, req, webhook)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -127,15 +145,15 @@ func (s *WebhooksService) UpdateWebhook(webhookID string, updateWebhookRequest *
 }
 
 // DeleteWebhook ....
-func (s *WebhooksService) DeleteWebhook(webhookID string) (*Response, error) {
+func (s *WebhooksService) DeleteWebhook(ctx context.Context, webhookID string) (*Response, error) {
 	path := webhooksBasePath + "/" + webhookID
 
-	req, err := s.client.NewRequest("DELETE", path, nil)
+	req, err := s.client.NewRequest(ctx, "DELETE", path, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := s.client.Do(req, nil)
+	resp, err := s.client.Do(ctx, req, nil)
 	if err != nil {
 		return resp, err
 	}